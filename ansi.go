@@ -0,0 +1,76 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// SuperscriptANSIRenderer renders superscript nodes for terminals, which cannot raise
+// text, by wrapping the content in ANSI SGR escape sequences. Control characters in the
+// content are dropped, so it cannot start escape sequences of its own.
+//
+// The content is followed by a full SGR reset (ESC [ 0 m), so styles opened by the
+// surrounding text are not restored afterwards.
+type SuperscriptANSIRenderer struct {
+	sgr           string
+	unicodeDigits bool
+}
+
+// ANSIOption configures a SuperscriptANSIRenderer.
+type ANSIOption func(*SuperscriptANSIRenderer)
+
+// WithANSIStyle returns an ANSIOption that styles superscripts with the given SGR
+// parameters, such as "2" for dim or "2;3" for dim italic, instead of the default dim.
+func WithANSIStyle(sgr string) ANSIOption {
+	return func(r *SuperscriptANSIRenderer) {
+		r.sgr = sgr
+	}
+}
+
+// WithANSIUnicodeDigits returns an ANSIOption that also writes the digits 0-9 as their
+// Unicode superscript forms, so x^2^ appears as x². Other characters are unchanged.
+func WithANSIUnicodeDigits() ANSIOption {
+	return func(r *SuperscriptANSIRenderer) {
+		r.unicodeDigits = true
+	}
+}
+
+// NewSuperscriptANSIRenderer returns a new SuperscriptANSIRenderer with the given options.
+func NewSuperscriptANSIRenderer(opts ...ANSIOption) renderer.NodeRenderer {
+	r := &SuperscriptANSIRenderer{sgr: "2"}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SuperscriptANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSuperscript, r.renderSuperscript)
+}
+
+// superscriptDigits holds the Unicode superscript forms of the digits 0-9.
+var superscriptDigits = [10]rune{'⁰', '¹', '²', '³', '⁴', '⁵', '⁶', '⁷', '⁸', '⁹'}
+
+func (r *SuperscriptANSIRenderer) renderSuperscript(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("\x1b[0m")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("\x1b[")
+	_, _ = w.WriteString(r.sgr)
+	_ = w.WriteByte('m')
+	for _, c := range string(resolveReferences(util.UnescapePunctuations(nodeContent(n, source)))) {
+		switch {
+		case c < 0x20 || c == 0x7f:
+			// Control characters could inject escape sequences of their own.
+		case r.unicodeDigits && c >= '0' && c <= '9':
+			_, _ = w.WriteRune(superscriptDigits[c-'0'])
+		default:
+			_, _ = w.WriteRune(c)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,55 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark/renderer"
+)
+
+func TestSuperscriptANSIRenderer(t *testing.T) {
+	testCases := []struct {
+		desc string
+		nr   renderer.NodeRenderer
+		md   string
+		want string
+	}{
+		{
+			desc: "ANSI: default dim style",
+			nr:   NewSuperscriptANSIRenderer(),
+			md:   "x^2^ and y^n+1^",
+			want: "x\x1b[2m2\x1b[0m and y\x1b[2mn+1\x1b[0m\n",
+		},
+		{
+			desc: "ANSI: custom style",
+			nr:   NewSuperscriptANSIRenderer(WithANSIStyle("2;3")),
+			md:   "x^2^",
+			want: "x\x1b[2;3m2\x1b[0m\n",
+		},
+		{
+			desc: "ANSI: Unicode digits",
+			nr:   NewSuperscriptANSIRenderer(WithANSIUnicodeDigits()),
+			md:   "x^10^ and y^2n^",
+			want: "x\x1b[2m¹⁰\x1b[0m and y\x1b[2m²n\x1b[0m\n",
+		},
+		{
+			desc: "ANSI: control characters are dropped",
+			nr:   NewSuperscriptANSIRenderer(),
+			md:   "x^a\x1b[31mb^",
+			want: "x\x1b[2ma[31mb\x1b[0m\n",
+		},
+		{
+			desc: "ANSI: character references are resolved",
+			nr:   NewSuperscriptANSIRenderer(),
+			md:   "x^&deg;^",
+			want: "x\x1b[2m°\x1b[0m\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := renderWith(t, tc.nr, tc.md); got != tc.want {
+				t.Errorf("ANSI output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package superscript
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// SuperscriptAsciiDocRenderer renders superscript nodes as AsciiDoc ^content^ markup.
+// The characters that start AsciiDoc inline formatting, ^ ~ * _ ` and #, are escaped
+// with a backslash so that the content is not read as further markup.
+type SuperscriptAsciiDocRenderer struct{}
+
+// NewSuperscriptAsciiDocRenderer returns a new SuperscriptAsciiDocRenderer.
+func NewSuperscriptAsciiDocRenderer() renderer.NodeRenderer {
+	return &SuperscriptAsciiDocRenderer{}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SuperscriptAsciiDocRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSuperscript, r.renderSuperscript)
+}
+
+// asciiDocSpecial lists the characters that start AsciiDoc inline formatting and are
+// escaped with a backslash inside superscript content.
+const asciiDocSpecial = "^~*_`#"
+
+func (r *SuperscriptAsciiDocRenderer) renderSuperscript(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_ = w.WriteByte('^')
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('^')
+	for _, b := range resolveReferences(util.UnescapePunctuations(nodeContent(n, source))) {
+		if strings.IndexByte(asciiDocSpecial, b) != -1 {
+			_ = w.WriteByte('\\')
+		}
+		_ = w.WriteByte(b)
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,76 @@
+package superscript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// plainTextRenderer renders paragraphs and text verbatim, standing in for the rest of
+// a non-HTML target renderer in tests.
+type plainTextRenderer struct{}
+
+func (plainTextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindParagraph, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			_ = w.WriteByte('\n')
+		}
+		return ast.WalkContinue, nil
+	})
+	reg.Register(ast.KindText, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			_, _ = w.Write(n.(*ast.Text).Segment.Value(source))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// renderWith parses md with the superscript extension and renders it with nr and
+// plainTextRenderer.
+func renderWith(t *testing.T, nr renderer.NodeRenderer, md string) string {
+	t.Helper()
+	source := []byte(md)
+	doc := goldmark.New(goldmark.WithExtensions(NewSuperscript())).Parser().Parse(text.NewReader(source))
+	return renderNode(t, nr, source, doc)
+}
+
+// renderNode renders n with nr and plainTextRenderer.
+func renderNode(t *testing.T, nr renderer.NodeRenderer, source []byte, n ast.Node) string {
+	t.Helper()
+	r := renderer.NewRenderer(renderer.WithNodeRenderers(
+		util.Prioritized(plainTextRenderer{}, 1000),
+		util.Prioritized(nr, 100),
+	))
+	var buf bytes.Buffer
+	if err := r.Render(&buf, source, n); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestSuperscriptAsciiDocRenderer(t *testing.T) {
+	if got, want := renderWith(t, NewSuperscriptAsciiDocRenderer(), "x^2^ and y^n+1^"), "x^2^ and y^n+1^\n"; got != want {
+		t.Errorf("AsciiDoc output = %q, want %q", got, want)
+	}
+
+	if got, want := renderWith(t, NewSuperscriptAsciiDocRenderer(), "x^a*b_c^"), "x^a\\*b\\_c^\n"; got != want {
+		t.Errorf("AsciiDoc output = %q, want %q", got, want)
+	}
+
+	if got, want := renderWith(t, NewSuperscriptAsciiDocRenderer(), "x^&deg;^"), "x^°^\n"; got != want {
+		t.Errorf("AsciiDoc output = %q, want %q", got, want)
+	}
+
+	// The parser never produces a caret inside content, so build the node by hand.
+	source := []byte("a^b")
+	n := NewSuperscriptNode()
+	n.AppendChild(n, ast.NewTextSegment(text.NewSegment(0, len(source))))
+	if got, want := renderNode(t, NewSuperscriptAsciiDocRenderer(), source, n), "^a\\^b^"; got != want {
+		t.Errorf("AsciiDoc output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// autoIDTransformer numbers the superscripts of a document in document order for
+// WithAutoID and WithFirstOccurrence. Numbering starts again at 1 for every document.
+type autoIDTransformer struct{}
+
+// NewAutoIDTransformer returns the AST transformer that numbers superscripts for
+// WithAutoID and WithFirstOccurrence. The numbers follow the final document, so it must
+// run after any transformer that adds or removes superscripts.
+func NewAutoIDTransformer() parser.ASTTransformer {
+	return autoIDTransformer{}
+}
+
+// Transform implements parser.ASTTransformer.
+func (autoIDTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	seq := 0
+	_ = Walk(doc, reader.Source(), func(n *Node) error {
+		seq++
+		n.seq = seq
+		return nil
+	})
+}
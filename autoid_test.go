@@ -0,0 +1,67 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestSuperscriptAutoID(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAutoID("")),
+		),
+	)
+
+	testCases := []TestCase{
+		{
+			desc: "Auto ID: sequential ids in document order",
+			md:   "x^2^ and y^3^\n\nz^4^",
+			html: "<p>x<sup id=\"sup-1\">2</sup> and y<sup id=\"sup-2\">3</sup></p>\n<p>z<sup id=\"sup-3\">4</sup></p>",
+		},
+		{
+			desc: "Auto ID: numbering restarts for each document",
+			md:   `a^1^`,
+			html: `<p>a<sup id="sup-1">1</sup></p>`,
+		},
+	}
+	// Convert every case twice so a counter kept across documents would show up.
+	runTestCases(t, mdTest, append(testCases, testCases...))
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAutoID("fn-"), WithTitleFunc(func(content []byte) string {
+				return string(content)
+			})),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(idTransformer("e1"), 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Auto ID: explicit id is kept",
+			md:   `x^2^ and y^3^`,
+			html: `<p>x<sup id="e1" title="2">2</sup> and y<sup id="fn-2" title="3">3</sup></p>`,
+		},
+	})
+}
+
+// idTransformer sets its id on the first superscript of a document.
+type idTransformer string
+
+func (id idTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	first := true
+	_ = Walk(doc, reader.Source(), func(n *Node) error {
+		if first {
+			n.SetAttributeString("id", []byte(id))
+			first = false
+		}
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// BasicSuperscriptHTMLRenderer renders superscript nodes as plain <sup> elements with
+// the attributes set on the node. Unlike SuperscriptHTMLRenderer it has no html.Config
+// and no options: attributes are written without SuperscriptAttributeFilter, and the
+// content is left to the renderer of the text nodes inside the superscript.
+type BasicSuperscriptHTMLRenderer struct{}
+
+// NewBasicSuperscriptHTMLRenderer returns a new BasicSuperscriptHTMLRenderer.
+func NewBasicSuperscriptHTMLRenderer() renderer.NodeRenderer {
+	return &BasicSuperscriptHTMLRenderer{}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *BasicSuperscriptHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSuperscript, r.renderSuperscript)
+}
+
+func (r *BasicSuperscriptHTMLRenderer) renderSuperscript(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</sup>")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<sup")
+	for _, attr := range n.Attributes() {
+		writeAttribute(w, attr)
+	}
+	_ = w.WriteByte('>')
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,50 @@
+package superscript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestBasicSuperscriptHTMLRenderer(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewSuperscriptParser(), 100),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(NewBasicSuperscriptHTMLRenderer(), 100),
+			),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Basic renderer: superscript",
+			md:   `x^2^ and a^<b>^`,
+			html: `<p>x<sup>2</sup> and a<sup>&lt;b&gt;</sup></p>`,
+		},
+	})
+
+	source := []byte(`x^2^`)
+	doc := mdTest.Parser().Parse(text.NewReader(source))
+	_ = Walk(doc, source, func(n *Node) error {
+		n.SetAttributeString("class", "exp")
+		n.SetAttributeString("onclick", `"x"`)
+		return nil
+	})
+	var buf bytes.Buffer
+	if err := mdTest.Renderer().Render(&buf, source, doc); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := buf.String(), "<p>x<sup class=\"exp\" onclick=\"&quot;x&quot;\">2</sup></p>\n"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package superscript
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// delimiterTransformer matches superscripts delimited by the non-ASCII runes set with
+// WithDelimiters.
+//
+// goldmark only calls inline parsers at ASCII punctuation, so a delimiter such as the
+// fullwidth ＾ never reaches the parser. The transformer runs after parsing instead,
+// walks the text nodes outside code spans and superscripts, and splits each one around
+// the superscripts found in it. A superscript is therefore only matched within a single
+// run of text, and never across emphasis, links or other inline markup.
+type delimiterTransformer struct {
+	parser *superscriptParser
+}
+
+// NewDelimiterTransformer returns the AST transformer that matches the non-ASCII
+// delimiters set in cfg by WithDelimiters. It should run before the other superscript
+// transformers, so that they see the superscripts it adds.
+func NewDelimiterTransformer(cfg *Config) parser.ASTTransformer {
+	return delimiterTransformer{parser: &superscriptParser{cfg: *cfg}}
+}
+
+// Transform implements parser.ASTTransformer.
+func (t delimiterTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	if !hasWideDelimiter(t.parser.cfg.delimiters) {
+		return
+	}
+	source := reader.Source()
+	// Text inside raw <sup> HTML is left alone, as the parser does for carets. The raw
+	// <sup> depth is kept per parent as its inline children are visited in order.
+	depths := map[ast.Node]*rawSupDepth{}
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if n.Type() == ast.TypeInline {
+			d := depths[n.Parent()]
+			if d == nil {
+				d = &rawSupDepth{parent: n.Parent()}
+				depths[n.Parent()] = d
+			}
+			d.add(n, source)
+			if d.depth > 0 {
+				return ast.WalkSkipChildren, nil
+			}
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan, KindSuperscript:
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			texts = append(texts, n.(*ast.Text))
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range texts {
+		t.split(n, source, pc)
+	}
+}
+
+// split replaces n with text nodes for the runs between its superscripts and the
+// superscript nodes themselves.
+func (t delimiterTransformer) split(n *ast.Text, source []byte, pc parser.Context) {
+	parent := n.Parent()
+	if t.parser.disallowedParent(parent) {
+		return
+	}
+	seg := n.Segment
+	// A block reader, unlike text.NewReader, does not cache the line it peeked across
+	// SetPosition and guards PrecendingCharacter against invalid UTF-8 before seg
+	lines := text.NewSegments()
+	lines.Append(text.NewSegment(0, seg.Stop))
+	block := text.NewBlockReader(source, lines)
+	for i := 0; i < seg.Len(); {
+		r, size := utf8.DecodeRune(source[seg.Start+i : seg.Stop])
+		if r < utf8.RuneSelf || !t.parser.isDelimiter(r) {
+			i += size
+			continue
+		}
+		if t.parser.atLimit(pc, seg.Start+i) {
+			t.parser.reject(pc, RejectLimit)
+			i += size
+			continue
+		}
+		block.SetPosition(0, text.NewSegment(seg.Start+i, seg.Stop))
+		sup, ok := t.parser.counted(pc, t.parser.parseAlternate(block, pc, r)).(*Node)
+		if !ok {
+			i += size
+			continue
+		}
+		if i > 0 {
+			before := ast.NewTextSegment(text.NewSegment(seg.Start, seg.Start+i))
+			before.SetRaw(n.IsRaw())
+			parent.InsertBefore(parent, n, before)
+		}
+		parent.InsertBefore(parent, n, sup)
+		seg = seg.WithStart(sup.span.Stop)
+		i = 0
+	}
+	if seg.Len() > 0 || n.SoftLineBreak() || n.HardLineBreak() {
+		n.Segment = seg
+		return
+	}
+	parent.RemoveChild(parent, n)
+}
+
+// hasWideDelimiter reports whether delimiters contains a rune outside ASCII.
+func hasWideDelimiter(delimiters []rune) bool {
+	for _, r := range delimiters {
+		if r >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// isDelimiter reports whether r is one of the delimiters set by WithDelimiters.
+func (s *superscriptParser) isDelimiter(r rune) bool {
+	for _, d := range s.cfg.delimiters {
+		if d == r {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAlternate parses a superscript opened and closed by delimiter, a delimiter other
+// than the caret set by WithDelimiters. The content follows the default caret rules.
+func (s *superscriptParser) parseAlternate(block text.Reader, pc parser.Context, delimiter rune) ast.Node {
+	line, segment := block.PeekLine()
+	size := utf8.RuneLen(delimiter)
+	end := bytes.IndexRune(line[size:], delimiter)
+	if end == -1 {
+		return s.reject(pc, RejectUnclosed)
+	}
+	end += size
+
+	if reason := s.canOpen(block); reason != "" {
+		return s.reject(pc, reason)
+	}
+	content := line[size:end]
+	if len(content) == 0 {
+		return s.reject(pc, RejectEmpty)
+	}
+	if s.cfg.containsSpace(content) {
+		return s.reject(pc, RejectWhitespace)
+	}
+	if crossesLink(block, content, line[end+size:]) {
+		return s.reject(pc, RejectContext)
+	}
+	if !s.acceptContent(content) {
+		return s.reject(pc, RejectContent)
+	}
+
+	node := s.newNode()
+	node.span = text.NewSegment(segment.Start, segment.Start+end+size)
+	node.text.Segment = text.NewSegmentPadding(segment.Start+size, segment.Start+end, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
+	block.Advance(end + size)
+	return node
+}
@@ -0,0 +1,91 @@
+package superscript
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestSuperscriptDelimiters(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiters('^', '＾')),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Delimiters: caret",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Delimiters: fullwidth caret",
+			md:   `x＾2＾`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Delimiters: both in one paragraph",
+			md:   `x^2^ and y＾3＾`,
+			html: `<p>x<sup>2</sup> and y<sup>3</sup></p>`,
+		},
+		{
+			desc: "Delimiters: mismatched pair",
+			md:   `x^2＾ and y＾3^`,
+			html: `<p>x^2＾ and y＾3^</p>`,
+		},
+		{
+			desc: "Delimiters: other runes sharing the leading byte",
+			md:   `x＄2＄`,
+			html: `<p>x＄2＄</p>`,
+		},
+		{
+			desc: "Delimiters: fullwidth caret in a code span",
+			md:   "`x＾2＾` and *y＾3＾*",
+			html: `<p><code>x＾2＾</code> and <em>y<sup>3</sup></em></p>`,
+		},
+		{
+			desc: "Delimiters: retry after a rejected fullwidth caret",
+			md:   `＾00＾ and x＾2＾`,
+			html: `<p>＾00＾ and x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Delimiters: invalid UTF-8 before the delimiter",
+			md:   "\x9b＾2＾",
+			html: "<p>\x9b＾2＾</p>",
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiters('^', '~')),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Delimiters: ASCII delimiter",
+			md:   `x~2~ and x^2^ and x^2~`,
+			html: `<p>x<sup>2</sup> and x<sup>2</sup> and x^2~</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiters('＾')),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Delimiters: caret not configured",
+			md:   `x^2^ and y＾3＾`,
+			html: `<p>x^2^ and y<sup>3</sup></p>`,
+		},
+	})
+
+	if _, err := NewSuperscriptE(WithDelimiters('^', ' ')); !errors.Is(err, ErrInvalidDelimiter) {
+		t.Errorf("NewSuperscriptE(WithDelimiters('^', ' ')) error = %v, want %v", err, ErrInvalidDelimiter)
+	}
+}
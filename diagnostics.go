@@ -0,0 +1,30 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/parser"
+)
+
+// Diagnostic describes a problem found while parsing superscripts in strict mode.
+type Diagnostic struct {
+	// Offset is the byte offset of the offending caret in the source.
+	Offset int
+
+	// Message describes the problem.
+	Message string
+}
+
+// diagnosticsKey is the parser.Context key under which diagnostics are collected.
+var diagnosticsKey = parser.NewContextKey()
+
+// Diagnostics returns the diagnostics recorded in pc while parsing with WithStrict, in
+// source order. Pass the same context to the parser with parser.WithContext to
+// retrieve them after conversion.
+func Diagnostics(pc parser.Context) []Diagnostic {
+	diagnostics, _ := pc.Get(diagnosticsKey).([]Diagnostic)
+	return diagnostics
+}
+
+// addDiagnostic records a diagnostic in pc.
+func addDiagnostic(pc parser.Context, offset int, message string) {
+	pc.Set(diagnosticsKey, append(Diagnostics(pc), Diagnostic{Offset: offset, Message: message}))
+}
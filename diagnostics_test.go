@@ -0,0 +1,68 @@
+package superscript
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestDiagnostics(t *testing.T) {
+	testCases := []struct {
+		desc string
+		opts []SuperscriptOption
+		md   string
+		html string
+		want []Diagnostic
+	}{
+		{
+			desc: "Diagnostics: unmatched caret is reported with its offset",
+			opts: []SuperscriptOption{WithStrict()},
+			md:   "E=mc^2 and a^2^\nthen y^3",
+			html: "<p>E=mc^2 and a<sup>2</sup>\nthen y^3</p>\n",
+			want: []Diagnostic{
+				{Offset: 4, Message: "unmatched opening caret"},
+				{Offset: 22, Message: "unmatched opening caret"},
+			},
+		},
+		{
+			desc: "Diagnostics: trailing caret at end of input",
+			opts: []SuperscriptOption{WithStrict()},
+			md:   "x^",
+			html: "<p>x^</p>\n",
+			want: []Diagnostic{
+				{Offset: 1, Message: "unmatched opening caret"},
+			},
+		},
+		{
+			desc: "Diagnostics: carets after whitespace are not openers",
+			opts: []SuperscriptOption{WithStrict()},
+			md:   "a ^ b and ^c",
+			html: "<p>a ^ b and ^c</p>\n",
+		},
+		{
+			desc: "Diagnostics: nothing recorded without strict mode",
+			md:   "E=mc^2",
+			html: "<p>E=mc^2</p>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			md := goldmark.New(goldmark.WithExtensions(NewSuperscript(tc.opts...)))
+			pc := parser.NewContext()
+			var buf bytes.Buffer
+			if err := md.Convert([]byte(tc.md), &buf, parser.WithContext(pc)); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tc.html {
+				t.Errorf("html = %q, want %q", buf.String(), tc.html)
+			}
+			if got := Diagnostics(pc); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Diagnostics() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
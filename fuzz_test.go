@@ -0,0 +1,67 @@
+package superscript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// fuzzOptions are the option sets FuzzParse renders every input with, chosen to reach
+// the parser's alternative code paths.
+var fuzzOptions = [][]SuperscriptOption{
+	nil,
+	{WithDoubleCaret(), WithTower(), WithShowDelimiters(), WithSourceAttribute()},
+	{WithMultiline(" "), WithLineContinuation(), WithTrimTrailingPunctuation(), WithQuotedContent()},
+	{WithImplicitClose(nil), WithParenImplicitClose(true), WithIsotopeMode(), WithPositionAttributes(), WithCloseAtParagraphEnd()},
+	{WithCloseDelimiter('°'), WithDelimiters('^', '＾', '~'), WithSkipInMath(), WithSkipCJKContext()},
+	{WithoutStrikethroughDeference(), WithLiteralTripleCaret(), WithSymmetricOnly(), WithEncodeStrayCarets()},
+	{WithInlineContent(), WithAutoID(""), WithTrimZeroWidth(), WithUnicodeOutput(), WithUnicodePartialMarker()},
+	{WithStrict(), WithStats(), WithMaxPerDocument(3), WithRejectInvalidUTF8(), WithContentEntities(EntitiesNamed), WithSignNormalization()},
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		`x^2^`, `a^2^ + b^2^ = c^2^`, `x ^2^`, `^2^ x`, `x^^2^^`, `x^^^2^^^`, `x^2 y^`,
+		"x^a\\\nb^", "e^x\ny^", "x^2\r\n^", `x^"a b"^`, `x^(n+1)`, `e^(-(x-m)/2)`, `2^2^2^`,
+		`^14^C`, `[a^b](c^d)`, `x^a[b^](c)`, "`x^2^`", `$x^2$`, `x^&deg;^`, `x^&#x1F600;^`,
+		`x^\^^`, `x^2°`, `x＾2＾`, "x^1​0^", `中文^字^中文`, `~~x^2^~~`, `x^*a*^`,
+		"\xff^\xfe^", "x^\x00^", `^`, `^^`, `x^`, "x^\n",
+	} {
+		f.Add(seed)
+	}
+
+	renderers := make([]goldmark.Markdown, len(fuzzOptions))
+	for i, opts := range fuzzOptions {
+		renderers[i] = goldmark.New(goldmark.WithExtensions(extension.GFM, NewSuperscript(opts...)))
+	}
+
+	f.Fuzz(func(t *testing.T, md string) {
+		for i, m := range renderers {
+			var first, second bytes.Buffer
+			if err := m.Convert([]byte(md), &first); err != nil {
+				t.Fatalf("options %d: Convert() error = %v", i, err)
+			}
+			if err := m.Convert([]byte(md), &second); err != nil {
+				t.Fatalf("options %d: Convert() error = %v", i, err)
+			}
+			if !bytes.Equal(first.Bytes(), second.Bytes()) {
+				t.Fatalf("options %d: output differs between runs:\n%s\n%s", i, first.Bytes(), second.Bytes())
+			}
+		}
+
+		// Visiting reads the content of every superscript node back from the source
+		source := []byte(md)
+		doc := renderers[0].Parser().Parse(text.NewReader(source))
+		if err := Visit(doc, source, fuzzVisitor{}); err != nil {
+			t.Fatalf("Visit() error = %v", err)
+		}
+	})
+}
+
+type fuzzVisitor struct{}
+
+func (fuzzVisitor) VisitSuperscript([]byte, []ast.Attribute) error { return nil }
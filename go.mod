@@ -6,5 +6,9 @@ replace github.com/zmtcreative/gm-subscript => ../gm-subscript
 
 require (
 	github.com/yuin/goldmark v1.7.13
+	github.com/yuin/goldmark-meta v1.1.0
 	github.com/zmtcreative/gm-subscript v0.0.0
+	golang.org/x/text v0.22.0
 )
+
+require gopkg.in/yaml.v2 v2.3.0 // indirect
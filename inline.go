@@ -0,0 +1,78 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// inlineContentTransformer parses the content of superscripts as inline markdown for
+// WithInlineContent.
+type inlineContentTransformer struct {
+	parser parser.Parser
+}
+
+// NewInlineContentTransformer returns the AST transformer that parses superscript
+// content as inline markdown with p for WithInlineContent. p is normally the parser of
+// the document itself, so the content is parsed with the same inline syntax.
+func NewInlineContentTransformer(p parser.Parser) parser.ASTTransformer {
+	return inlineContentTransformer{parser: p}
+}
+
+// Transform implements parser.ASTTransformer.
+func (t inlineContentTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var nodes []*Node
+	_ = Walk(doc, reader.Source(), func(n *Node) error {
+		nodes = append(nodes, n)
+		return nil
+	})
+	for _, n := range nodes {
+		t.parseContent(n, reader.Source(), pc)
+	}
+}
+
+// parseContent replaces the flat text content of n with the inlines parsed from it.
+// Content that does not come from a single source segment, such as multi-line or
+// normalized content, is left flat, as is content that would parse into anything other
+// than one paragraph or that contains a superscript of its own.
+func (t inlineContentTransformer) parseContent(n *Node, source []byte, pc parser.Context) {
+	content, ok := n.FirstChild().(*ast.Text)
+	if !ok || content.NextSibling() != nil {
+		return
+	}
+
+	// Reference definitions belong to the outer document, so links can use them.
+	// Inline parsers running on the content see it as inside a superscript.
+	inner := parser.NewContext()
+	for _, ref := range pc.References() {
+		inner.AddReference(ref)
+	}
+	inner.Set(insideKey, true)
+	lines := text.NewSegments()
+	lines.Append(content.Segment)
+	doc := t.parser.Parse(text.NewBlockReader(source, lines), parser.WithContext(inner))
+
+	paragraph, ok := doc.FirstChild().(*ast.Paragraph)
+	if !ok || paragraph.NextSibling() != nil || containsSuperscript(paragraph) {
+		return
+	}
+	n.RemoveChildren(n)
+	for c := paragraph.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		n.AppendChild(n, c)
+		c = next
+	}
+}
+
+// containsSuperscript reports whether n has a superscript node among its descendants.
+func containsSuperscript(n ast.Node) bool {
+	found := false
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && c.Kind() == KindSuperscript {
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
@@ -0,0 +1,97 @@
+package superscript
+
+import (
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindIsotope is a NodeKind of the Isotope node.
+var KindIsotope = ast.NewNodeKind("Isotope")
+
+// Isotope represents isotope notation parsed with WithIsotopeMode: a superscript mass
+// number followed by an element symbol. Its children are the superscript node and the
+// text of the symbol. Renderers without a function for KindIsotope render the children
+// as usual.
+type Isotope struct {
+	ast.BaseInline
+}
+
+// Kind implements ast.Node.Kind and returns the node kind for isotope nodes.
+func (*Isotope) Kind() ast.NodeKind {
+	return KindIsotope
+}
+
+// Dump implements ast.Node.Dump and prints the node structure for debugging.
+func (n *Isotope) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewIsotopeNode returns a new Isotope node.
+func NewIsotopeNode() *Isotope {
+	return &Isotope{}
+}
+
+// parseIsotope parses isotope notation such as ^14^C at the reader's position for
+// WithIsotopeMode. The notation must start a word and the symbol must end one, and the
+// mass number must pass the configured content restrictions. It returns nil, without
+// advancing the reader, when there is no isotope notation there.
+func (s *superscriptParser) parseIsotope(block text.Reader) ast.Node {
+	before := block.PrecendingCharacter()
+	if unicode.IsLetter(before) || unicode.IsDigit(before) || before == '^' {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	end := 1
+	for end < len(line) && line[end] >= '0' && line[end] <= '9' {
+		end++
+	}
+	if end == 1 || end >= len(line) || line[end] != '^' {
+		return nil
+	}
+
+	// An element symbol is an upper case letter, optionally followed by a lower case one
+	symbol := end + 1
+	stop := symbol
+	if stop < len(line) && line[stop] >= 'A' && line[stop] <= 'Z' {
+		stop++
+		if stop < len(line) && line[stop] >= 'a' && line[stop] <= 'z' {
+			stop++
+		}
+	}
+	if stop == symbol || (stop < len(line) && unicode.IsLetter(rune(line[stop]))) {
+		return nil
+	}
+
+	// The mass number is superscript content, so the same content restrictions apply
+	if s.literalContext(block) != "" || !s.acceptContent(line[1:end]) {
+		return nil
+	}
+
+	sup := s.newNode()
+	sup.span = text.NewSegment(segment.Start, segment.Start+symbol)
+	sup.text.Segment = text.NewSegmentPadding(segment.Start+1, segment.Start+end, segment.Padding)
+	sup.AppendChild(sup, &sup.text)
+	s.normalize(sup, block.Source())
+
+	isotope := NewIsotopeNode()
+	isotope.AppendChild(isotope, sup)
+	isotope.AppendChild(isotope, ast.NewTextSegment(text.NewSegment(segment.Start+symbol, segment.Start+stop)))
+	block.Advance(stop)
+	return isotope
+}
+
+// renderIsotope renders an Isotope node as a span around its children.
+func (r *SuperscriptHTMLRenderer) renderIsotope(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span class="isotope"`)
+		writeAttributes(w, n.Attributes())
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</span>")
+	}
+	return ast.WalkContinue, nil
+}
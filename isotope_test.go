@@ -0,0 +1,71 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestSuperscriptIsotopeMode(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithIsotopeMode()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Isotope: carbon-14 at the start of a line",
+			md:   `^14^C dating`,
+			html: `<p><span class="isotope"><sup>14</sup>C</span> dating</p>`,
+		},
+		{
+			desc: "Isotope: uranium-235 after whitespace",
+			md:   `enriched ^235^U and (^238^U)`,
+			html: `<p>enriched <span class="isotope"><sup>235</sup>U</span> and (<span class="isotope"><sup>238</sup>U</span>)</p>`,
+		},
+		{
+			desc: "Isotope: two-letter symbol",
+			md:   `^60^Co.`,
+			html: `<p><span class="isotope"><sup>60</sup>Co</span>.</p>`,
+		},
+		{
+			desc: "Isotope: not an isotope",
+			md:   `x^2^ and 1^st^ and ^14^Carbon and ^a^C`,
+			html: `<p>x<sup>2</sup> and 1<sup>st</sup> and ^14^Carbon and ^a^C</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithIsotopeMode(), WithMinContentLength(3), WithSkipInMath()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Isotope: content restrictions apply to the mass number",
+			md:   `^14^C and ^235^U`,
+			html: `<p>^14^C and <span class="isotope"><sup>235</sup>U</span></p>`,
+		},
+		{
+			desc: "Isotope: left literal inside math",
+			md:   `$^235^U$ and ^235^U`,
+			html: `<p>$^235^U$ and <span class="isotope"><sup>235</sup>U</span></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Isotope: left literal without the option",
+			md:   `^14^C and (^238^U)`,
+			html: `<p>^14^C and (<sup>238</sup>U)</p>`,
+		},
+	})
+}
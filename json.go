@@ -0,0 +1,26 @@
+package superscript
+
+import (
+	"encoding/json"
+)
+
+// nodeJSON is the JSON form of a superscript node written by MarshalNode.
+type nodeJSON struct {
+	Kind    string `json:"kind"`
+	Content string `json:"content"`
+	Start   int    `json:"start"`
+	Stop    int    `json:"stop"`
+}
+
+// MarshalNode returns a JSON description of n for editor tooling, such as
+// {"kind":"Superscript","content":"2","start":1,"stop":4}. start and stop are the byte
+// offsets in source of the superscript, delimiters included, and are both 0 for nodes
+// that were not created by the parser.
+func MarshalNode(n *Node, source []byte) ([]byte, error) {
+	return json.Marshal(nodeJSON{
+		Kind:    n.Kind().String(),
+		Content: string(nodeContent(n, source)),
+		Start:   n.span.Start,
+		Stop:    n.span.Stop,
+	})
+}
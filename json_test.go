@@ -0,0 +1,46 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestMarshalNode(t *testing.T) {
+	source := []byte("x^2^ and y^n+1^")
+	doc := goldmark.New(goldmark.WithExtensions(NewSuperscript())).Parser().Parse(text.NewReader(source))
+
+	var got []string
+	err := Walk(doc, source, func(n *Node) error {
+		b, err := MarshalNode(n, source)
+		got = append(got, string(b))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"kind":"Superscript","content":"2","start":1,"stop":4}`,
+		`{"kind":"Superscript","content":"n+1","start":10,"stop":15}`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MarshalNode() called for %d nodes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MarshalNode() = %s, want %s", got[i], want[i])
+		}
+	}
+
+	n := NewSuperscriptNode()
+	n.AppendChild(n, ast.NewString([]byte(`"q"`)))
+	b, err := MarshalNode(n, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"kind":"Superscript","content":"\"q\"","start":0,"stop":0}`; string(b) != want {
+		t.Errorf("MarshalNode() = %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,45 @@
+package superscript
+
+import (
+	"strconv"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// countKey is the parser.Context key under which the number of superscripts parsed in
+// the document is kept for WithMaxPerDocument. Each document is parsed with a new
+// context, so the count starts over for every document.
+var countKey = parser.NewContextKey()
+
+// limitReportedKey is the parser.Context key that is set once the limit of
+// WithMaxPerDocument has been reported as a Diagnostic.
+var limitReportedKey = parser.NewContextKey()
+
+// atLimit reports whether the document parsed with pc already has the number of
+// superscripts allowed by WithMaxPerDocument. With WithStrict the first caret refused
+// this way is recorded as a Diagnostic at offset; later ones are not.
+func (s *superscriptParser) atLimit(pc parser.Context, offset int) bool {
+	if s.cfg.maxPerDocument <= 0 {
+		return false
+	}
+	count, _ := pc.Get(countKey).(int)
+	if count < s.cfg.maxPerDocument {
+		return false
+	}
+	if reported, _ := pc.Get(limitReportedKey).(bool); s.cfg.strict && !reported {
+		addDiagnostic(pc, offset, "more than "+strconv.Itoa(s.cfg.maxPerDocument)+" superscripts in the document")
+		pc.Set(limitReportedKey, true)
+	}
+	return true
+}
+
+// counted adds node, the result of a parse function, to the count kept for
+// WithMaxPerDocument and returns it.
+func (s *superscriptParser) counted(pc parser.Context, node ast.Node) ast.Node {
+	if node != nil && s.cfg.maxPerDocument > 0 {
+		count, _ := pc.Get(countKey).(int)
+		pc.Set(countKey, count+1)
+	}
+	return node
+}
@@ -0,0 +1,53 @@
+package superscript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestSuperscriptMaxPerDocument(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMaxPerDocument(2)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Max per document: later carets stay literal",
+			md:   "a^1^ b^2^ c^3^\n\nd^4^",
+			html: "<p>a<sup>1</sup> b<sup>2</sup> c^3^</p>\n<p>d^4^</p>",
+		},
+		{
+			desc: "Max per document: the count starts over for each document",
+			md:   `a^1^ b^2^`,
+			html: `<p>a<sup>1</sup> b<sup>2</sup></p>`,
+		},
+		{
+			desc: "Max per document: rejected carets are not counted",
+			md:   `a ^1^ b^2 c^3^ d^4^ e^5^`,
+			html: `<p>a ^1^ b^2 c<sup>3</sup> d<sup>4</sup> e^5^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptMaxPerDocumentDiagnostic(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(NewSuperscript(WithMaxPerDocument(1), WithStrict(), WithStats())))
+	pc := parser.NewContext()
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(`a^1^ b^2^ c^3^`), &buf, parser.WithContext(pc)); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := Diagnostics(pc)
+	if len(diagnostics) != 1 || diagnostics[0].Offset != 6 {
+		t.Errorf("Diagnostics() = %v, want one diagnostic at offset 6", diagnostics)
+	}
+	// Both carets of b^2^ and c^3^ reach the parser
+	if got := Stats(pc)[RejectLimit]; got != 4 {
+		t.Errorf("Stats()[RejectLimit] = %d, want 4", got)
+	}
+}
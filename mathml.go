@@ -0,0 +1,66 @@
+package superscript
+
+import (
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// SuperscriptMathMLRenderer renders superscript nodes as MathML msup elements, for
+// documents read with assistive technology that understands native math markup. It
+// writes HTML and is meant to replace the HTML superscript renderer, so register it at
+// a lower priority value than DefaultRendererPriority, which goldmark prefers:
+//
+//	goldmark.WithRendererOptions(renderer.WithNodeRenderers(
+//		util.Prioritized(superscript.NewSuperscriptMathMLRenderer(), superscript.DefaultRendererPriority-1),
+//	))
+//
+// The base of the power is the text before the superscript, which is not part of the
+// node, so each superscript is written with an empty base: x^2^ renders as
+// x<math><msup><mrow></mrow><mn>2</mn></msup></math>. Numeric content is written as
+// mn, alphabetic content as mi and anything else as mtext, after character references
+// such as &deg; have been resolved.
+type SuperscriptMathMLRenderer struct{}
+
+// NewSuperscriptMathMLRenderer returns a new SuperscriptMathMLRenderer.
+func NewSuperscriptMathMLRenderer() renderer.NodeRenderer {
+	return &SuperscriptMathMLRenderer{}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SuperscriptMathMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSuperscript, r.renderSuperscript)
+}
+
+func (r *SuperscriptMathMLRenderer) renderSuperscript(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	content := resolveReferences(util.UnescapePunctuations(nodeContent(n, source)))
+	element := mathMLElement(content)
+	_, _ = w.WriteString("<math><msup><mrow></mrow><")
+	_, _ = w.WriteString(element)
+	_ = w.WriteByte('>')
+	_, _ = w.Write(util.EscapeHTML(content))
+	_, _ = w.WriteString("</")
+	_, _ = w.WriteString(element)
+	_, _ = w.WriteString("></msup></math>")
+	return ast.WalkSkipChildren, nil
+}
+
+// mathMLElement returns the MathML token element for content: mn for a number, mi for
+// letters and mtext for anything else.
+func mathMLElement(content []byte) string {
+	if isNumeric(content) {
+		return "mn"
+	}
+	for _, c := range string(content) {
+		if !unicode.IsLetter(c) {
+			return "mtext"
+		}
+	}
+	return "mi"
+}
@@ -0,0 +1,45 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestSuperscriptMathMLRenderer(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(NewSuperscriptMathMLRenderer(), DefaultRendererPriority-1),
+			),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "MathML: numeric content",
+			md:   `x^2^ and 10^23^`,
+			html: `<p>x<math><msup><mrow></mrow><mn>2</mn></msup></math> and 10<math><msup><mrow></mrow><mn>23</mn></msup></math></p>`,
+		},
+		{
+			desc: "MathML: alphabetic content",
+			md:   `e^x^`,
+			html: `<p>e<math><msup><mrow></mrow><mi>x</mi></msup></math></p>`,
+		},
+		{
+			desc: "MathML: other content is escaped text",
+			md:   `x^n<1^`,
+			html: `<p>x<math><msup><mrow></mrow><mtext>n&lt;1</mtext></msup></math></p>`,
+		},
+		{
+			desc: "MathML: character references are resolved",
+			md:   `x^&deg;^`,
+			html: `<p>x<math><msup><mrow></mrow><mtext>°</mtext></msup></math></p>`,
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	meta "github.com/yuin/goldmark-meta"
+)
+
+func TestSuperscriptMetaDefaults(t *testing.T) {
+	keys := MetaKeys{Class: "sup_class", Lang: "sup_lang"}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			meta.New(meta.WithStoresInDocument()),
+			NewSuperscript(WithMetaDefaults(keys)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Meta defaults: class and lang from front matter",
+			md:   "---\nsup_class: exp\nsup_lang: fr\n---\nle 1^er^",
+			html: `<p>le 1<sup class="exp" lang="fr">er</sup></p>`,
+		},
+		{
+			desc: "Meta defaults: document without front matter",
+			md:   "x^2^",
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Meta defaults: entries that are not strings",
+			md:   "---\nsup_class: 3\n---\nx^2^",
+			html: `<p>x<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			meta.New(meta.WithStoresInDocument()),
+			NewSuperscript(WithMetaDefaults(keys), WithLang("de"), WithFootnoteSafeClass("md-sup")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Meta defaults: explicit options win",
+			md:   "---\nsup_class: exp\nsup_lang: fr\n---\nx^2^",
+			html: `<p>x<sup class="md-sup" lang="de">2</sup></p>`,
+		},
+	})
+}
@@ -0,0 +1,1018 @@
+package superscript
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Config holds the settings shared by the superscript parser and renderer. Create one
+// with NewConfig; the zero value is the default configuration.
+type Config struct {
+	// Parser settings.
+	skipInMath               bool
+	closeDelimiter           rune
+	requireWordBefore        bool
+	trimTrailingPunctuation  bool
+	strict                   bool
+	respectFootnotes         bool
+	multiline                bool
+	multilineSeparator       string
+	nodePool                 bool
+	requireNonNumeric        bool
+	literalTripleCaret       bool
+	allowedRunes             func(r rune) bool
+	doubleCaret              bool
+	doubleCaretOnly          bool
+	implicitClose            func(r rune) bool
+	implicitBoundary         bool
+	implicitCharge           bool
+	normalize                bool
+	normalizeForm            norm.Form
+	stats                    bool
+	inlineContent            bool
+	noStrikethroughDeference bool
+	encodeStrayCarets        bool
+	quotedContent            bool
+	symmetricOnly            bool
+	minContentLength         int
+	tower                    bool
+	skipCJKContext           bool
+	delimiters               []rune
+	trimZeroWidth            bool
+	rejectZeroWidth          bool
+	pairedPriority           bool
+	priorityBase             int
+	lineContinuation         bool
+	isotopeMode              bool
+	maxPerDocument           int
+	parenImplicitClose       bool
+	closeAtParagraphEnd      bool
+	whitespaceFunc           func(r rune) bool
+	rejectInvalidUTF8        bool
+	allowedScripts           []*unicode.RangeTable
+	keepParens               bool
+	disallowedParents        []ast.NodeKind
+
+	// Renderer settings.
+	contentEntities         EntityMode
+	deterministicAttributes bool
+	sourceAttribute         bool
+	renderHook              RenderHook
+	symbols                 map[string]string
+	titleFunc               func(content []byte) string
+	linkFunc                func(content []byte) (string, bool)
+	spanMode                bool
+	spanClass               string
+	adjacentSeparator       string
+	autoID                  bool
+	autoIDPrefix            string
+	escapeContentEntities   bool
+	srOnlyClass             string
+	srOnlyFunc              func(content []byte) string
+	itemprop                string
+	signNormalization       bool
+	lang                    string
+	classFunc               func(content []byte) string
+	footnoteSafeClass       string
+	metaKeys                MetaKeys
+	showDelimiters          bool
+	unicodeOutput           bool
+	unicodePartialMarker    bool
+	renderTransform         func(content []byte) []byte
+	bemBlock                string
+	delimiterFunc           bool
+	positionAttributes      bool
+	numerals                map[rune]rune
+	openFunc                func(content []byte) string
+	closeFunc               func(content []byte) string
+	delimiterClass          string
+	breakHint               int
+	wrapPrefix              string
+	wrapSuffix              string
+	contextClass            bool
+	occurrenceFunc          func(index int, content []byte) []ast.Attribute
+	debugComments           bool
+	pdfStyle                bool
+	flatten                 bool
+	citationFunc            func(content []byte) (string, bool)
+	romanceOrdinals         bool
+	commaSplit              bool
+	commaSeparator          string
+}
+
+// closer returns the rune that closes a superscript and its encoded length in bytes.
+func (c *Config) closer() (rune, int) {
+	if c.closeDelimiter == 0 {
+		return '^', 1
+	}
+	return c.closeDelimiter, utf8.RuneLen(c.closeDelimiter)
+}
+
+// isSpace reports whether r is whitespace, which ends or rejects a superscript: as
+// defined by WithWhitespaceFunc, or by unicode.IsSpace by default. Line endings are
+// always whitespace.
+func (c *Config) isSpace(r rune) bool {
+	if c.whitespaceFunc == nil || r == '\n' || r == '\r' {
+		return unicode.IsSpace(r)
+	}
+	return c.whitespaceFunc(r)
+}
+
+// containsSpace reports whether content contains whitespace as defined by isSpace.
+func (c *Config) containsSpace(content []byte) bool {
+	for _, r := range string(content) {
+		if c.isSpace(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// SuperscriptOption configures the superscript extension.
+type SuperscriptOption func(*Config)
+
+// NewConfig returns a Config with the given options applied.
+func NewConfig(opts ...SuperscriptOption) *Config {
+	c := &Config{}
+	c.apply(opts)
+	return c
+}
+
+// apply applies opts to c in order.
+func (c *Config) apply(opts []SuperscriptOption) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+var (
+	// ErrInvalidDelimiter is returned by NewSuperscriptE for an unusable delimiter.
+	ErrInvalidDelimiter = errors.New("superscript: invalid delimiter")
+
+	// ErrInvalidEntityMode is returned by NewSuperscriptE for an unknown EntityMode.
+	ErrInvalidEntityMode = errors.New("superscript: invalid entity mode")
+
+	// ErrInvalidLength is returned by NewSuperscriptE for a negative content length.
+	ErrInvalidLength = errors.New("superscript: invalid length")
+)
+
+// Validate reports the first invalid setting in c.
+func (c *Config) Validate() error {
+	if c.closeDelimiter != 0 && !validDelimiter(c.closeDelimiter) {
+		return fmt.Errorf("%w: close delimiter %q", ErrInvalidDelimiter, c.closeDelimiter)
+	}
+	for _, r := range c.delimiters {
+		if !validDelimiter(r) {
+			return fmt.Errorf("%w: delimiter %q", ErrInvalidDelimiter, r)
+		}
+	}
+	if c.contentEntities < EntitiesNone || c.contentEntities > EntitiesNumeric {
+		return fmt.Errorf("%w: %d", ErrInvalidEntityMode, c.contentEntities)
+	}
+	if c.minContentLength < 0 {
+		return fmt.Errorf("%w: minimum content length %d", ErrInvalidLength, c.minContentLength)
+	}
+	return nil
+}
+
+// resetInvalid restores the default of every setting that Validate rejects.
+func (c *Config) resetInvalid() {
+	if c.closeDelimiter != 0 && !validDelimiter(c.closeDelimiter) {
+		c.closeDelimiter = 0
+	}
+	for _, r := range c.delimiters {
+		if !validDelimiter(r) {
+			c.delimiters = nil
+			break
+		}
+	}
+	if c.contentEntities < EntitiesNone || c.contentEntities > EntitiesNumeric {
+		c.contentEntities = EntitiesNone
+	}
+	if c.minContentLength < 0 {
+		c.minContentLength = 0
+	}
+}
+
+// validDelimiter reports whether r can delimit superscripts: a valid rune that is
+// neither whitespace nor a control character.
+func validDelimiter(r rune) bool {
+	return utf8.ValidRune(r) && !unicode.IsSpace(r) && !unicode.IsControl(r)
+}
+
+// EntityMode controls how superscript content is encoded as HTML character references.
+type EntityMode int
+
+const (
+	// EntitiesNone renders content as ordinary text. This is the default.
+	EntitiesNone EntityMode = iota
+	// EntitiesNamed encodes every content character as a named entity where one
+	// is known, and as a numeric reference otherwise.
+	EntitiesNamed
+	// EntitiesNumeric encodes every content character as a numeric reference.
+	EntitiesNumeric
+)
+
+// WithContentEntities returns a SuperscriptOption that encodes superscript content
+// as HTML character references, for consumers that cannot handle raw UTF-8.
+func WithContentEntities(mode EntityMode) SuperscriptOption {
+	return func(c *Config) {
+		c.contentEntities = mode
+	}
+}
+
+// WithSkipInMath returns a SuperscriptOption that leaves carets between $...$ math
+// delimiters untouched, since LaTeX uses ^ for its own superscripts.
+func WithSkipInMath() SuperscriptOption {
+	return func(c *Config) {
+		c.skipInMath = true
+	}
+}
+
+// WithCloseDelimiter returns a SuperscriptOption that closes superscripts with r instead
+// of a second caret, so that x^2° renders as x<sup>2</sup>. Superscripts still open with ^.
+func WithCloseDelimiter(r rune) SuperscriptOption {
+	return func(c *Config) {
+		c.closeDelimiter = r
+	}
+}
+
+// WithDeterministicAttributes returns a SuperscriptOption that renders attributes sorted
+// by name, so output is byte-for-byte stable regardless of the order they were added.
+func WithDeterministicAttributes() SuperscriptOption {
+	return func(c *Config) {
+		c.deterministicAttributes = true
+	}
+}
+
+// WithRequireWordBoundaryBefore returns a SuperscriptOption that only parses a superscript
+// when the character before the opening caret is a letter or digit, so carets after
+// punctuation such as )^2^ or ,^2^ stay literal.
+func WithRequireWordBoundaryBefore() SuperscriptOption {
+	return func(c *Config) {
+		c.requireWordBefore = true
+	}
+}
+
+// WithTrimTrailingPunctuation returns a SuperscriptOption that moves trailing periods,
+// commas, semicolons and colons out of the superscript, so x^2.^ renders as x<sup>2</sup>.
+// Content made up entirely of punctuation is left unchanged.
+func WithTrimTrailingPunctuation() SuperscriptOption {
+	return func(c *Config) {
+		c.trimTrailingPunctuation = true
+	}
+}
+
+// WithSourceAttribute returns a SuperscriptOption that adds a data-md attribute holding
+// the original markdown of each superscript, delimiters included, for debugging and
+// editor round-tripping.
+func WithSourceAttribute() SuperscriptOption {
+	return func(c *Config) {
+		c.sourceAttribute = true
+	}
+}
+
+// WithStrict returns a SuperscriptOption that records a Diagnostic for every opening
+// caret without a closing delimiter. Rendering is unchanged; use Diagnostics to
+// retrieve the results from the parser.Context after conversion.
+func WithStrict() SuperscriptOption {
+	return func(c *Config) {
+		c.strict = true
+	}
+}
+
+// WithRespectFootnotes returns a SuperscriptOption that never parses a superscript
+// directly after an opening bracket, leaving [^1^] to the footnote extension instead of
+// rendering it as [<sup>1</sup>].
+func WithRespectFootnotes() SuperscriptOption {
+	return func(c *Config) {
+		c.respectFootnotes = true
+	}
+}
+
+// WithMultiline returns a SuperscriptOption that lets superscript content continue
+// across soft line breaks in a paragraph when the closing delimiter is not on the same
+// line. Each line break is replaced by separator, typically "" or " ".
+func WithMultiline(separator string) SuperscriptOption {
+	return func(c *Config) {
+		c.multiline = true
+		c.multilineSeparator = separator
+	}
+}
+
+// WithNodePool returns a SuperscriptOption that takes superscript nodes from a shared
+// pool instead of allocating them. Nodes only return to the pool through Release.
+func WithNodePool() SuperscriptOption {
+	return func(c *Config) {
+		c.nodePool = true
+	}
+}
+
+// RenderHook renders a superscript node in place of the HTML renderer. It returns true
+// if it has written the output for this call, or false to fall back to the default
+// <sup> rendering. When the hook handles the entering call the node's children are
+// not rendered, so the hook is responsible for the content.
+type RenderHook func(w util.BufWriter, source []byte, n ast.Node, entering bool) (bool, error)
+
+// WithRenderHook returns a SuperscriptOption that gives hook the first chance to render
+// every superscript node.
+func WithRenderHook(hook RenderHook) SuperscriptOption {
+	return func(c *Config) {
+		c.renderHook = hook
+	}
+}
+
+// DefaultSymbols maps superscript content to the HTML entities used by
+// WithSymbolSubstitution when no mapping is given.
+var DefaultSymbols = map[string]string{
+	"TM": "&trade;",
+	"SM": "&#8480;",
+	"R":  "&reg;",
+	"C":  "&copy;",
+}
+
+// WithSymbolSubstitution returns a SuperscriptOption that renders superscripts whose
+// entire content is a key of symbols as the mapped HTML, without a surrounding <sup>
+// since the glyphs are already raised. The values are written as-is. A nil map uses
+// DefaultSymbols, so Acme^TM^ renders as Acme&trade;.
+func WithSymbolSubstitution(symbols map[string]string) SuperscriptOption {
+	return func(c *Config) {
+		if symbols == nil {
+			symbols = DefaultSymbols
+		}
+		c.symbols = symbols
+	}
+}
+
+// WithTitleFunc returns a SuperscriptOption that adds a title attribute computed from the
+// content of each superscript, for hover tooltips. An empty result adds no title.
+func WithTitleFunc(fn func(content []byte) string) SuperscriptOption {
+	return func(c *Config) {
+		c.titleFunc = fn
+	}
+}
+
+// WithLinkFunc returns a SuperscriptOption that wraps the content of a superscript in a
+// link when fn returns ok, for reference markers such as claim^[3]^. The href is escaped,
+// and dangerous URLs are dropped unless the renderer is unsafe. fn is called when the
+// superscript is opened and again when it is closed.
+func WithLinkFunc(fn func(content []byte) (href string, ok bool)) SuperscriptOption {
+	return func(c *Config) {
+		c.linkFunc = fn
+	}
+}
+
+// WithRequireNonNumeric returns a SuperscriptOption that leaves purely numeric
+// superscripts such as word^1^ literal, since they are easily confused with footnote
+// markers. Content with at least one non-digit, such as x^2a^ or x^n+1^, still parses.
+func WithRequireNonNumeric() SuperscriptOption {
+	return func(c *Config) {
+		c.requireNonNumeric = true
+	}
+}
+
+// WithSpanMode returns a SuperscriptOption that renders superscripts as
+// <span class="..."> instead of <sup>, for design systems that style superscripts
+// themselves. An empty class uses "superscript". The class is added to any class set on
+// the node.
+func WithSpanMode(class string) SuperscriptOption {
+	return func(c *Config) {
+		if class == "" {
+			class = "superscript"
+		}
+		c.spanMode = true
+		c.spanClass = class
+	}
+}
+
+// WithLiteralTripleCaret returns a SuperscriptOption that treats every run of three or
+// more consecutive carets as literal text, so decorative rows such as ^^^ never open or
+// close a superscript.
+func WithLiteralTripleCaret() SuperscriptOption {
+	return func(c *Config) {
+		c.literalTripleCaret = true
+	}
+}
+
+// WithAllowedRunes returns a SuperscriptOption that leaves a superscript literal unless
+// every rune of its content satisfies allowed, for restricting superscripts to a
+// controlled vocabulary such as exponents. Whitespace and carets are rejected as before.
+func WithAllowedRunes(allowed func(r rune) bool) SuperscriptOption {
+	return func(c *Config) {
+		c.allowedRunes = allowed
+	}
+}
+
+// WithAdjacentSeparator returns a SuperscriptOption that writes sep between two
+// superscripts with nothing in between, so a^2^^2^ renders as
+// a<sup>2</sup>sep<sup>2</sup>. The AST is unchanged and sep is written as-is, so it
+// may be an entity such as "&thinsp;".
+func WithAdjacentSeparator(sep string) SuperscriptOption {
+	return func(c *Config) {
+		c.adjacentSeparator = sep
+	}
+}
+
+// WithAutoID returns a SuperscriptOption that gives every superscript an id made of
+// prefix and its position in the document, such as sup-1 and sup-2, for deep-linking.
+// An empty prefix uses "sup-". Numbering restarts for each document, and an id already
+// set on the node is kept.
+func WithAutoID(prefix string) SuperscriptOption {
+	return func(c *Config) {
+		if prefix == "" {
+			prefix = "sup-"
+		}
+		c.autoID = true
+		c.autoIDPrefix = prefix
+	}
+}
+
+// WithDoubleCaret returns a SuperscriptOption that also parses ^^content^^ as a
+// superscript, an explicit form that cannot be mistaken for a footnote reference.
+// Single carets may appear inside it, so x^^a^b^^ renders as x<sup>a^b</sup>. The
+// single-caret form keeps working unless WithDoubleCaretOnly is given too.
+func WithDoubleCaret() SuperscriptOption {
+	return func(c *Config) {
+		c.doubleCaret = true
+	}
+}
+
+// WithDoubleCaretOnly returns a SuperscriptOption that parses only the ^^content^^ form
+// of WithDoubleCaret, leaving single-caret superscripts such as x^2^ literal.
+func WithDoubleCaretOnly() SuperscriptOption {
+	return func(c *Config) {
+		c.doubleCaret = true
+		c.doubleCaretOnly = true
+	}
+}
+
+// WithImplicitClose returns a SuperscriptOption that lets a caret without a closing
+// delimiter raise the run of runes straight after it that satisfy class, Pandoc style,
+// so x^23 renders as x<sup>23</sup>. The run ends at the first other rune, whitespace or
+// caret. A nil class accepts digits. Without this option such carets stay literal.
+func WithImplicitClose(class func(r rune) bool) SuperscriptOption {
+	return func(c *Config) {
+		if class == nil {
+			class = unicode.IsDigit
+		}
+		c.implicitClose = class
+	}
+}
+
+// WithEscapeContentEntities returns a SuperscriptOption that shows entity and character
+// references in superscript content as written, so x^&times;^ renders as
+// x<sup>&amp;times;</sup> rather than as a multiplication sign. WithContentEntities takes
+// precedence when both are given.
+func WithEscapeContentEntities() SuperscriptOption {
+	return func(c *Config) {
+		c.escapeContentEntities = true
+	}
+}
+
+// WithCloseOnBoundary returns a SuperscriptOption that, like WithImplicitClose, lets an
+// unclosed caret raise the run of runes after it that satisfy class, but only when the
+// run ends at a word boundary, for chemistry notation such as Na^+ and SO^2-. A run
+// followed by a letter or digit stays literal. A nil class accepts ionic charges: runs
+// of digits, + and - that end in a sign, so Fe^3+ is raised but x^2 stays literal.
+func WithCloseOnBoundary(class func(r rune) bool) SuperscriptOption {
+	return func(c *Config) {
+		c.implicitCharge = class == nil
+		if class == nil {
+			class = isChargeRune
+		}
+		c.implicitClose = class
+		c.implicitBoundary = true
+	}
+}
+
+// isChargeRune reports whether r is a digit or a sign, as used in ionic charges.
+func isChargeRune(r rune) bool {
+	return unicode.IsDigit(r) || r == '+' || r == '-'
+}
+
+// WithSROnly returns a SuperscriptOption that appends a visually hidden span with the
+// text fn returns for the content of each superscript, so assistive technology can read
+// x^2^ as x<sup>2<span class="sr-only"> squared</span></sup>. An empty class uses
+// "sr-only", and an empty result adds no span. The text is escaped.
+func WithSROnly(class string, fn func(content []byte) string) SuperscriptOption {
+	return func(c *Config) {
+		if class == "" {
+			class = "sr-only"
+		}
+		c.srOnlyClass = class
+		c.srOnlyFunc = fn
+	}
+}
+
+// WithNormalize returns a SuperscriptOption that converts superscript content to the
+// Unicode normalization form f, such as norm.NFC, so composed and decomposed spellings
+// of the same text produce identical output.
+func WithNormalize(f norm.Form) SuperscriptOption {
+	return func(c *Config) {
+		c.normalize = true
+		c.normalizeForm = f
+	}
+}
+
+// WithItemprop returns a SuperscriptOption that adds a microdata itemprop attribute
+// with the given name, such as "exponent", to every superscript. An itemprop already
+// set on the node is kept.
+func WithItemprop(name string) SuperscriptOption {
+	return func(c *Config) {
+		c.itemprop = name
+	}
+}
+
+// WithSignNormalization returns a SuperscriptOption that renders a hyphen at the start
+// of superscript content as a minus sign (U+2212), so x^-2^ renders as x<sup>−2</sup>.
+// Hyphens elsewhere in the content are unchanged.
+func WithSignNormalization() SuperscriptOption {
+	return func(c *Config) {
+		c.signNormalization = true
+	}
+}
+
+// WithLang returns a SuperscriptOption that adds a lang attribute with the given
+// language tag, such as "fr", to every superscript. A lang set on the node wins.
+func WithLang(lang string) SuperscriptOption {
+	return func(c *Config) {
+		c.lang = lang
+	}
+}
+
+// WithStats returns a SuperscriptOption that counts the carets left literal for each
+// reason, for tuning documents. Rendering is unchanged; use Stats to retrieve the counts
+// from the parser.Context after conversion.
+func WithStats() SuperscriptOption {
+	return func(c *Config) {
+		c.stats = true
+	}
+}
+
+// WithInlineContent returns a SuperscriptOption that parses superscript content as
+// inline markdown, so x^*2*^ renders as x<sup><em>2</em></sup>. Superscripts nested in
+// the content are not supported; such content, and content that spans several lines,
+// is kept as plain text.
+func WithInlineContent() SuperscriptOption {
+	return func(c *Config) {
+		c.inlineContent = true
+	}
+}
+
+// WithoutStrikethroughDeference returns a SuperscriptOption that stops leaving doubled
+// carets to a caret-based strikethrough extension. A caret directly after the opening
+// one becomes content, so a^^2^ renders as a<sup>^2</sup> instead of a^<sup>2</sup>.
+// Use it only when no other extension claims ^^; if one does, whichever inline parser
+// has the higher priority sees the carets first. WithDoubleCaret takes precedence.
+func WithoutStrikethroughDeference() SuperscriptOption {
+	return func(c *Config) {
+		c.noStrikethroughDeference = true
+	}
+}
+
+// WithEncodeStrayCarets returns a SuperscriptOption that writes every caret left as
+// literal text outside code spans as the character reference &#94;, so downstream tools
+// cannot mistake it for markup. The carets are found in the parsed text after
+// superscripts have been recognized.
+func WithEncodeStrayCarets() SuperscriptOption {
+	return func(c *Config) {
+		c.encodeStrayCarets = true
+	}
+}
+
+// WithQuotedContent returns a SuperscriptOption that accepts spaces in content wrapped in
+// straight double quotes and renders it without the quotes, for labels such as
+// x^"a b"^, which renders as x<sup>a b</sup>. Content with unbalanced quotes, or with
+// spaces and no quotes, stays literal.
+func WithQuotedContent() SuperscriptOption {
+	return func(c *Config) {
+		c.quotedContent = true
+	}
+}
+
+// WithSymmetricOnly returns a SuperscriptOption that leaves every caret in a paragraph
+// literal when the paragraph has an odd number of carets, since some caret there cannot
+// be paired. Carets escaped with a backslash or inside code spans are not counted.
+func WithSymmetricOnly() SuperscriptOption {
+	return func(c *Config) {
+		c.symmetricOnly = true
+	}
+}
+
+// WithMinContentLength returns a SuperscriptOption that leaves superscripts with fewer
+// than n runes of content literal, to avoid false positives on stray carets around
+// single letters. The default, 1, accepts any non-empty content.
+func WithMinContentLength(n int) SuperscriptOption {
+	return func(c *Config) {
+		c.minContentLength = n
+	}
+}
+
+// WithClassFunc returns a SuperscriptOption that adds the class fn computes from the
+// content of each superscript, for styling numeric and other exponents differently. An
+// empty result adds no class, and the class is added to any class set on the node.
+func WithClassFunc(fn func(content []byte) string) SuperscriptOption {
+	return func(c *Config) {
+		c.classFunc = fn
+	}
+}
+
+// WithFootnoteSafeClass returns a SuperscriptOption that adds class to every superscript
+// rendered by this extension, so CSS can tell them apart from the <sup> footnote
+// references of goldmark's footnote extension. An empty class uses "superscript".
+func WithFootnoteSafeClass(class string) SuperscriptOption {
+	return func(c *Config) {
+		if class == "" {
+			class = "superscript"
+		}
+		c.footnoteSafeClass = class
+	}
+}
+
+// MetaKeys names the document metadata entries read by WithMetaDefaults. An empty name
+// is not read.
+type MetaKeys struct {
+	// Class names the entry holding a class for every superscript, such as "sup_class".
+	Class string
+
+	// Lang names the entry holding a lang attribute for every superscript.
+	Lang string
+}
+
+// WithMetaDefaults returns a SuperscriptOption that reads per-document defaults from the
+// document metadata at render time, such as front matter stored in the document by
+// goldmark-meta's WithStoresInDocument. The class is used only when neither
+// WithClassFunc nor WithFootnoteSafeClass is set, and the lang only without WithLang.
+// Entries that are not strings are ignored.
+func WithMetaDefaults(keys MetaKeys) SuperscriptOption {
+	return func(c *Config) {
+		c.metaKeys = keys
+	}
+}
+
+// WithTower returns a SuperscriptOption that reads a chain of three or more carets with
+// no whitespace in between as a right-associative exponent tower, so 2^2^2^ renders as
+// 2<sup>2<sup>2</sup></sup>. The last caret closes every level. A chain such as
+// x^2^y^3^ is read as a tower too, so separate adjacent superscripts need whitespace
+// between them when this option is set.
+func WithTower() SuperscriptOption {
+	return func(c *Config) {
+		c.tower = true
+	}
+}
+
+// WithShowDelimiters returns a SuperscriptOption that keeps the delimiters of each
+// parsed superscript in the output, so x^2^ renders as x<sup>^2^</sup>, for "show
+// markup" views. Superscripts without delimiters, such as those closed implicitly or
+// created outside the parser, are rendered as usual.
+func WithShowDelimiters() SuperscriptOption {
+	return func(c *Config) {
+		c.showDelimiters = true
+	}
+}
+
+// WithDelimiterClass returns a SuperscriptOption that shows the delimiters like
+// WithShowDelimiters and wraps each one in a span with class, so they can be styled
+// apart from the content.
+func WithDelimiterClass(class string) SuperscriptOption {
+	return func(c *Config) {
+		c.showDelimiters = true
+		c.delimiterClass = class
+	}
+}
+
+// WithSkipCJKContext returns a SuperscriptOption that leaves a caret literal when both
+// the character before it and the first character of its content are Han, Hiragana or
+// Katakana, since a caret inside Chinese or Japanese text is almost never a
+// superscript. Carets next to Latin text still parse.
+func WithSkipCJKContext() SuperscriptOption {
+	return func(c *Config) {
+		c.skipCJKContext = true
+	}
+}
+
+// WithDelimiters returns a SuperscriptOption that recognizes superscripts delimited by
+// any rune in rs, such as the fullwidth ＾ typed with many input methods, in place of
+// the caret alone. Include '^' to keep the caret syntax. A superscript opens and closes
+// with the same rune, so ^x＾ is left literal. Options that change the caret syntax,
+// such as WithCloseDelimiter and WithDoubleCaret, apply to the caret only. Delimiters
+// outside ASCII are matched after parsing, within a single run of text.
+func WithDelimiters(rs ...rune) SuperscriptOption {
+	return func(c *Config) {
+		c.delimiters = rs
+	}
+}
+
+// WithTrimZeroWidth returns a SuperscriptOption that removes zero-width runes, such as
+// U+200B ZERO WIDTH SPACE and U+FEFF BYTE ORDER MARK left over from copy and paste, from
+// the content of each superscript. Content made up only of zero-width runes is left
+// literal. Zero-width joiners are removed too, so emoji sequences that rely on them are
+// split into their parts.
+func WithTrimZeroWidth() SuperscriptOption {
+	return func(c *Config) {
+		c.trimZeroWidth = true
+	}
+}
+
+// WithRejectZeroWidth returns a SuperscriptOption that leaves superscripts whose content
+// contains a zero-width rune literal, for callers who prefer to see such text unchanged
+// rather than silently cleaned up by WithTrimZeroWidth. It takes precedence over
+// WithTrimZeroWidth.
+func WithRejectZeroWidth() SuperscriptOption {
+	return func(c *Config) {
+		c.rejectZeroWidth = true
+	}
+}
+
+// WithUnicodeOutput returns a SuperscriptOption that writes superscripts whose every
+// character has a Unicode superscript form as those characters instead of a <sup>
+// element, so x^2^ renders as x². The digits, + - − = ( ) and the letters i and n have
+// such forms. Other superscripts are rendered as usual.
+func WithUnicodeOutput() SuperscriptOption {
+	return func(c *Config) {
+		c.unicodeOutput = true
+	}
+}
+
+// WithUnicodePartialMarker returns a SuperscriptOption that marks the superscripts
+// WithUnicodeOutput could not convert with data-unicode="partial", so CSS or scripts can
+// tell them apart. The marker is the same whether some or none of the characters have a
+// Unicode superscript form. It has no effect without WithUnicodeOutput.
+func WithUnicodePartialMarker() SuperscriptOption {
+	return func(c *Config) {
+		c.unicodePartialMarker = true
+	}
+}
+
+// WithPairedPriority returns a SuperscriptOption that registers the parser and renderer
+// at base, for documents that also use the sibling gm-subscript extension, which
+// registers its parser at SubscriptParserPriority. goldmark tries lower priorities
+// first, so at a trigger the two share, such as ~ with WithDelimiters, a base below
+// SubscriptParserPriority tries the superscript parser first and a base above it tries
+// the subscript parser first, whatever order the extensions are registered in. With
+// the same priority the extension registered first wins. The transformers keep their
+// places relative to the parser.
+func WithPairedPriority(base int) SuperscriptOption {
+	return func(c *Config) {
+		c.pairedPriority = true
+		c.priorityBase = base
+	}
+}
+
+// WithRenderTransform returns a SuperscriptOption that passes the content of each
+// superscript through fn just before the HTML renderer writes it, without changing the
+// document, so one parsed document can be rendered differently by renderers configured
+// with different transforms. fn receives a copy of the content it may modify, and the
+// result is written with the usual escaping.
+func WithRenderTransform(fn func(content []byte) []byte) SuperscriptOption {
+	return func(c *Config) {
+		c.renderTransform = fn
+	}
+}
+
+// WithLineContinuation returns a SuperscriptOption that lets a backslash at the end of a
+// line continue an unclosed superscript on the next line of the paragraph, so a long
+// exponent can be wrapped. The backslash and line ending are removed and the lines are
+// joined directly. Without this option, or WithMultiline, superscripts stay on one line.
+func WithLineContinuation() SuperscriptOption {
+	return func(c *Config) {
+		c.lineContinuation = true
+	}
+}
+
+// WithBEM returns a SuperscriptOption that adds the BEM element class {block}__sup to
+// every superscript, such as formula__sup. With WithClassFunc the computed class is
+// used as a modifier instead, so a result of "numeric" adds formula__sup--numeric.
+// Document metadata classes from WithMetaDefaults are not used with this option.
+func WithBEM(block string) SuperscriptOption {
+	return func(c *Config) {
+		c.bemBlock = block
+	}
+}
+
+// WithIsotopeMode returns a SuperscriptOption that reads a superscript mass number
+// followed by an element symbol, such as ^14^C or ^235^U, as isotope notation. The pair
+// becomes an Isotope node, which the HTML renderer writes as
+// <span class="isotope"><sup>14</sup>C</span>. Isotope notation may start a line or
+// follow whitespace, where a superscript otherwise may not.
+func WithIsotopeMode() SuperscriptOption {
+	return func(c *Config) {
+		c.isotopeMode = true
+	}
+}
+
+// WithMaxPerDocument returns a SuperscriptOption that leaves every caret literal once n
+// superscripts have been parsed in a document, to bound the work a shared rendering
+// service does for one input. With WithStrict the first caret refused this way is
+// recorded as a Diagnostic. A value of 0 or less means no limit, the default.
+func WithMaxPerDocument(n int) SuperscriptOption {
+	return func(c *Config) {
+		c.maxPerDocument = n
+	}
+}
+
+// WithDelimiterFunc returns a SuperscriptOption that writes the results of opening and
+// closing around the content of each superscript in place of the <sup> and </sup> tags,
+// for markers such as ⟦sup⟧2⟦/sup⟧ that a script hydrates later. The results are
+// written as they are, without escaping, and no attributes are rendered. A nil
+// function writes nothing.
+func WithDelimiterFunc(opening, closing func(content []byte) string) SuperscriptOption {
+	return func(c *Config) {
+		c.delimiterFunc = true
+		c.openFunc = opening
+		c.closeFunc = closing
+	}
+}
+
+// WithPositionAttributes returns a SuperscriptOption that adds data-start and data-stop
+// attributes holding the byte offsets of the content of each superscript in the
+// source, for editors that map rendered elements back to the text. Superscripts whose
+// content does not come from the source get neither attribute.
+func WithPositionAttributes() SuperscriptOption {
+	return func(c *Config) {
+		c.positionAttributes = true
+	}
+}
+
+// WithParenImplicitClose returns a SuperscriptOption that lets a balanced parenthesized
+// group right after an unclosed caret be the whole superscript, so x^(n+1) renders as
+// x<sup>n+1</sup>, or as x<sup>(n+1)</sup> when keepParens is true. Groups may nest;
+// an unbalanced group, or one containing whitespace or a caret, is left as it is.
+func WithParenImplicitClose(keepParens bool) SuperscriptOption {
+	return func(c *Config) {
+		c.parenImplicitClose = true
+		c.keepParens = keepParens
+	}
+}
+
+// ArabicIndicDigits maps the digits 0-9 to the Arabic-Indic digits, for use with
+// WithNumeralMapping.
+var ArabicIndicDigits = map[rune]rune{
+	'0': '٠', '1': '١', '2': '٢', '3': '٣', '4': '٤',
+	'5': '٥', '6': '٦', '7': '٧', '8': '٨', '9': '٩',
+}
+
+// WithNumeralMapping returns a SuperscriptOption that writes the digits 0-9 in the
+// content of each superscript as the runes numerals maps them to, such as
+// ArabicIndicDigits, for localized documents. Other runes are written unchanged. Only
+// the output changes; the document keeps the original digits.
+func WithNumeralMapping(numerals map[rune]rune) SuperscriptOption {
+	return func(c *Config) {
+		c.numerals = numerals
+	}
+}
+
+// WithDisallowedParents returns a SuperscriptOption that leaves carets literal inside
+// nodes of the given kinds, including inside inline nodes nested in them, such as
+// ast.KindHeading to keep headings and the anchors generated from them free of
+// superscripts.
+func WithDisallowedParents(kinds ...ast.NodeKind) SuperscriptOption {
+	return func(c *Config) {
+		c.disallowedParents = kinds
+	}
+}
+
+// WithBreakHint returns a SuperscriptOption that writes a <wbr> after each superscript
+// whose content is longer than threshold runes, so that browsers can break a line after
+// long exponents in narrow columns. With html.WithXHTML it is written as <wbr />.
+func WithBreakHint(threshold int) SuperscriptOption {
+	return func(c *Config) {
+		c.breakHint = threshold
+	}
+}
+
+// WithWrap returns a SuperscriptOption that writes prefix before and suffix after the
+// element of each superscript, such as markers for a later processing step. Unlike
+// WithDelimiterFunc, the tags are kept. Both are written as given, without escaping.
+func WithWrap(prefix, suffix string) SuperscriptOption {
+	return func(c *Config) {
+		c.wrapPrefix = prefix
+		c.wrapSuffix = suffix
+	}
+}
+
+// WithContextClass returns a SuperscriptOption that adds a class to each superscript for
+// the emphasis it appears in, in-em inside *emphasis* and in-strong inside **strong
+// emphasis**, for styling superscripts by their context.
+func WithContextClass() SuperscriptOption {
+	return func(c *Config) {
+		c.contextClass = true
+	}
+}
+
+// WithFirstOccurrence returns a SuperscriptOption that calls fn for each superscript
+// with its index in the document, starting at 0, and its content, and adds the
+// attributes fn returns, such as an anchor id for the first use of a glossary term
+// when index is 0. The index restarts for each document.
+func WithFirstOccurrence(fn func(index int, content []byte) []ast.Attribute) SuperscriptOption {
+	return func(c *Config) {
+		c.occurrenceFunc = fn
+	}
+}
+
+// WithDebugComments returns a SuperscriptOption that brackets the output of each
+// superscript with <!-- sup:start --> and <!-- sup:end --> comments, to find what the
+// extension wrote when debugging templates.
+func WithDebugComments() SuperscriptOption {
+	return func(c *Config) {
+		c.debugComments = true
+	}
+}
+
+// WithCloseAtParagraphEnd returns a SuperscriptOption that closes a superscript left
+// unclosed at the end of a paragraph, so that "the answer is x^2" renders the 2 as a
+// superscript. The rest of the line becomes the content, which the usual rules still
+// apply to: a caret followed by a space, or on an earlier line, stays literal.
+func WithCloseAtParagraphEnd() SuperscriptOption {
+	return func(c *Config) {
+		c.closeAtParagraphEnd = true
+	}
+}
+
+// WithWhitespaceFunc returns a SuperscriptOption that uses isSpace to decide which runes
+// count as whitespace inside superscripts, in place of unicode.IsSpace. A rune it
+// rejects may then appear in the content, such as a thin space (U+2009) when only
+// ASCII space and tab should end a superscript. Line endings are always whitespace.
+func WithWhitespaceFunc(isSpace func(r rune) bool) SuperscriptOption {
+	return func(c *Config) {
+		c.whitespaceFunc = isSpace
+	}
+}
+
+// WithPDFStyle returns a SuperscriptOption that gives each superscript the inline style
+// vertical-align:super;font-size:smaller, for HTML-to-PDF tools that do not apply the
+// default styling of <sup>. A style attribute already set on the node is kept.
+func WithPDFStyle() SuperscriptOption {
+	return func(c *Config) {
+		c.pdfStyle = true
+	}
+}
+
+// WithFlatten returns a SuperscriptOption that renders superscripts as their content
+// text alone, without tags, attributes or any of the other renderer options, so that
+// E=mc^2^ renders as E=mc2. It is meant for a separate goldmark instance that renders
+// excerpts such as meta descriptions.
+func WithFlatten() SuperscriptOption {
+	return func(c *Config) {
+		c.flatten = true
+	}
+}
+
+// WithRejectInvalidUTF8 returns a SuperscriptOption that leaves carets literal around
+// content that is not valid UTF-8, including encoded surrogate halves. By default such
+// content is accepted and its bytes are passed through unchanged.
+func WithRejectInvalidUTF8() SuperscriptOption {
+	return func(c *Config) {
+		c.rejectInvalidUTF8 = true
+	}
+}
+
+// WithCitation returns a SuperscriptOption that renders a superscript as a citation
+// marker when fn returns ok: its content is wrapped in <a href="#cite-id" rel="cite">,
+// with id as returned by fn. Other superscripts render as usual, or as links when
+// WithLinkFunc also matches them.
+func WithCitation(fn func(content []byte) (id string, ok bool)) SuperscriptOption {
+	return func(c *Config) {
+		c.citationFunc = fn
+	}
+}
+
+// WithAllowedScripts returns a SuperscriptOption that leaves a superscript literal
+// unless every rune of its content belongs to one of scripts, such as unicode.Latin
+// and unicode.Common for a Latin-script site, to avoid parsing carets in pasted text of
+// other scripts. Digits and most punctuation are in unicode.Common.
+func WithAllowedScripts(scripts ...*unicode.RangeTable) SuperscriptOption {
+	return func(c *Config) {
+		c.allowedScripts = scripts
+	}
+}
+
+// WithRomanceOrdinals returns a SuperscriptOption that renders a superscript o or a
+// right after a digit as the masculine or feminine ordinal indicator, so that 1^o^
+// renders as 1º and 2^a^ as 2ª, as in Spanish and Portuguese. Other superscripts
+// render as usual.
+func WithRomanceOrdinals() SuperscriptOption {
+	return func(c *Config) {
+		c.romanceOrdinals = true
+	}
+}
+
+// WithCommaSplit returns a SuperscriptOption that renders a superscript with
+// comma-separated content as one element per part, joined by separator, so that
+// x^2,3,4^ renders as x<sup>2</sup>,<sup>3</sup>,<sup>4</sup>. separator is written
+// as given, and an empty one writes the comma. The parts are written as text.
+func WithCommaSplit(separator string) SuperscriptOption {
+	return func(c *Config) {
+		if separator == "" {
+			separator = ","
+		}
+		c.commaSplit = true
+		c.commaSeparator = separator
+	}
+}
@@ -0,0 +1,114 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestSuperscriptSharedConfig(t *testing.T) {
+	cfg := NewConfig(WithCloseDelimiter('°'), WithSourceAttribute())
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	mdTest := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewSuperscriptParserWithConfig(cfg), 100),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(NewSuperscriptHTMLRendererWithConfig(cfg), 100),
+			),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Shared config: parser uses close delimiter",
+			md:   `x^2°`,
+			html: `<p>x<sup data-md="^2°">2</sup></p>`,
+		},
+		{
+			desc: "Shared config: caret no longer closes",
+			md:   `x^2^`,
+			html: `<p>x^2^</p>`,
+		},
+	})
+}
+
+func TestNewConfigValidate(t *testing.T) {
+	if err := NewConfig().Validate(); err != nil {
+		t.Errorf("NewConfig().Validate() error = %v, want nil", err)
+	}
+	if err := NewConfig(WithCloseDelimiter('\n')).Validate(); err == nil {
+		t.Error("NewConfig(WithCloseDelimiter('\\n')).Validate() error = nil, want error")
+	}
+}
+
+func TestNewSuperscriptParserOptions(t *testing.T) {
+	if NewSuperscriptParser() != NewSuperscriptParser() {
+		t.Error("NewSuperscriptParser() returned different parsers, want the shared default")
+	}
+
+	mdTest := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewSuperscriptParser(WithCloseDelimiter('°'), WithMinContentLength(2)), 100),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(NewSuperscriptHTMLRenderer(), 100),
+			),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Configured parser: close delimiter",
+			md:   `x^10° and x^2°`,
+			html: `<p>x<sup>10</sup> and x^2°</p>`,
+		},
+		{
+			desc: "Configured parser: caret no longer closes",
+			md:   `x^10^`,
+			html: `<p>x^10^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptConfigure(t *testing.T) {
+	ext := NewSuperscript(WithSourceAttribute())
+	ext.Configure(WithCloseDelimiter('°'))
+	if err := ext.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(ext),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Configure: options are applied on top of earlier ones",
+			md:   `x^2° and x^2^`,
+			html: `<p>x<sup data-md="^2°">2</sup> and x^2^</p>`,
+		},
+	})
+
+	// The settings were copied by Extend, so this no longer affects mdTest
+	ext.Configure(WithCloseDelimiter('^'))
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Configure: no effect after Extend",
+			md:   `x^2°`,
+			html: `<p>x<sup data-md="^2°">2</sup></p>`,
+		},
+	})
+}
@@ -0,0 +1,41 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// SuperscriptRSTRenderer renders superscript nodes as the reStructuredText :sup: role.
+// Backticks and backslashes in the content are escaped with a backslash, so they cannot
+// end the role early.
+//
+// Interpreted text must be separated from the surrounding words, so the role is wrapped
+// in escaped spaces, which reStructuredText removes: x^2^ renders as x\ :sup:`2`\ .
+type SuperscriptRSTRenderer struct{}
+
+// NewSuperscriptRSTRenderer returns a new SuperscriptRSTRenderer.
+func NewSuperscriptRSTRenderer() renderer.NodeRenderer {
+	return &SuperscriptRSTRenderer{}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SuperscriptRSTRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSuperscript, r.renderSuperscript)
+}
+
+func (r *SuperscriptRSTRenderer) renderSuperscript(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("`\\ ")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("\\ :sup:`")
+	for _, b := range resolveReferences(util.UnescapePunctuations(nodeContent(n, source))) {
+		if b == '`' || b == '\\' {
+			_ = w.WriteByte('\\')
+		}
+		_ = w.WriteByte(b)
+	}
+	return ast.WalkSkipChildren, nil
+}
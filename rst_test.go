@@ -0,0 +1,42 @@
+package superscript
+
+import (
+	"testing"
+)
+
+func TestSuperscriptRSTRenderer(t *testing.T) {
+	testCases := []struct {
+		desc string
+		md   string
+		want string
+	}{
+		{
+			desc: "RST: simple superscript",
+			md:   "x^2^ and y^n+1^",
+			want: "x\\ :sup:`2`\\  and y\\ :sup:`n+1`\\ \n",
+		},
+		{
+			desc: "RST: backticks are escaped",
+			md:   "x^a`b^",
+			want: "x\\ :sup:`a\\`b`\\ \n",
+		},
+		{
+			desc: "RST: backslashes are escaped",
+			md:   `x^a\b^`,
+			want: "x\\ :sup:`a\\\\b`\\ \n",
+		},
+		{
+			desc: "RST: character references are resolved",
+			md:   "x^&deg;^",
+			want: "x\\ :sup:`°`\\ \n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := renderWith(t, NewSuperscriptRSTRenderer(), tc.md); got != tc.want {
+				t.Errorf("RST output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
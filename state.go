@@ -0,0 +1,20 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/parser"
+)
+
+// insideKey is the parser.Context key that is set in the context used to parse the
+// content of superscripts for WithInlineContent.
+var insideKey = parser.NewContextKey()
+
+// InsideSuperscript reports whether the superscript parser is currently parsing a
+// superscript in pc.
+//
+// Superscript content is normally kept as a single text segment that other inline
+// parsers never see, so this is false. With WithInlineContent the content is parsed
+// again, and this is true for the inline parsers that run on it.
+func InsideSuperscript(pc parser.Context) bool {
+	inside, _ := pc.Get(insideKey).(bool)
+	return inside
+}
@@ -0,0 +1,89 @@
+package superscript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// insideRecorder is an inline parser for '@' that records InsideSuperscript each time
+// it is triggered, and otherwise leaves the text alone.
+type insideRecorder struct {
+	seen *[]bool
+}
+
+func (p insideRecorder) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (p insideRecorder) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	*p.seen = append(*p.seen, InsideSuperscript(pc))
+	return nil
+}
+
+func (p insideRecorder) CloseBlock(parent ast.Node, pc parser.Context) {}
+
+func TestInsideSuperscript(t *testing.T) {
+	var seen []bool
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(insideRecorder{seen: &seen}, 500)),
+		),
+	)
+
+	// The '@' inside the superscript is part of its content and never reaches the
+	// cooperating parser; the ones around it are parsed outside any superscript.
+	var buf bytes.Buffer
+	if err := mdTest.Convert([]byte("a@ x^b@c^ d@"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "<p>a@ x<sup>b@c</sup> d@</p>\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if len(seen) != 2 || seen[0] || seen[1] {
+		t.Errorf("InsideSuperscript() seen = %v, want [false false]", seen)
+	}
+
+	// With inline content the content is parsed again, and the cooperating parser sees
+	// the flag set for the '@' inside the superscript only.
+	seen = nil
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithInlineContent()),
+		),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(insideRecorder{seen: &seen}, 500)),
+		),
+	)
+	buf.Reset()
+	if err := mdTest.Convert([]byte("a@ x^b@*c*^ d@"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "<p>a@ x<sup>b@<em>c</em></sup> d@</p>\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if len(seen) != 3 || seen[0] || seen[1] || !seen[2] {
+		t.Errorf("InsideSuperscript() seen = %v, want [false false true]", seen)
+	}
+
+	pc := parser.NewContext()
+	if InsideSuperscript(pc) {
+		t.Error("InsideSuperscript() = true for a new context")
+	}
+	reader := text.NewReader([]byte("x^2^"))
+	reader.Advance(1)
+	if NewSuperscriptParser().Parse(ast.NewParagraph(), reader, pc) == nil {
+		t.Fatal("expected a superscript node")
+	}
+	if InsideSuperscript(pc) {
+		t.Error("InsideSuperscript() = true after Parse returned")
+	}
+}
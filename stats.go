@@ -0,0 +1,59 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// Reasons for which the parser leaves a caret as literal text, as counted by WithStats.
+const (
+	// RejectUnclosed counts carets without a closing delimiter.
+	RejectUnclosed = "unclosed"
+	// RejectEmpty counts superscripts with no content, such as x^^.
+	RejectEmpty = "empty"
+	// RejectWhitespace counts carets whose content would contain whitespace.
+	RejectWhitespace = "whitespace"
+	// RejectLeadingWhitespace counts carets at the start of a line or after whitespace.
+	RejectLeadingWhitespace = "leading-whitespace"
+	// RejectContext counts carets refused because of the text before them by
+	// WithRequireWordBoundaryBefore, WithRespectFootnotes or WithSkipInMath.
+	RejectContext = "context"
+	// RejectDelimiter counts carets that are part of another delimiter, such as ^^ or
+	// a run of three or more carets with WithLiteralTripleCaret.
+	RejectDelimiter = "delimiter"
+	// RejectUnpaired counts carets in blocks with an odd number of carets, with
+	// WithSymmetricOnly.
+	RejectUnpaired = "unpaired"
+	// RejectContent counts superscripts whose content was refused by an option such as
+	// WithRequireNonNumeric or WithAllowedRunes.
+	RejectContent = "content"
+	// RejectLimit counts carets after the number of superscripts allowed by
+	// WithMaxPerDocument was reached.
+	RejectLimit = "limit"
+)
+
+// statsKey is the parser.Context key under which rejection counts are collected.
+var statsKey = parser.NewContextKey()
+
+// Stats returns the number of carets left literal for each rejection reason while
+// parsing with WithStats, keyed by the Reject constants. Pass the same context to the
+// parser with parser.WithContext to retrieve them after conversion. A caret is counted
+// once, for the first rule it fails.
+func Stats(pc parser.Context) map[string]int {
+	stats, _ := pc.Get(statsKey).(map[string]int)
+	return stats
+}
+
+// reject counts a rejection for reason in pc when WithStats is set and returns nil, so
+// that parse functions can return its result.
+func (s *superscriptParser) reject(pc parser.Context, reason string) ast.Node {
+	if s.cfg.stats {
+		stats := Stats(pc)
+		if stats == nil {
+			stats = map[string]int{}
+			pc.Set(statsKey, stats)
+		}
+		stats[reason]++
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package superscript
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestStats(t *testing.T) {
+	testCases := []struct {
+		desc string
+		opts []SuperscriptOption
+		md   string
+		html string
+		want map[string]int
+	}{
+		// A rejected caret is only skipped, so later carets in the same text are
+		// counted too, such as the closing caret of a rejected superscript.
+		{
+			desc: "Stats: mixed valid and invalid carets",
+			opts: []SuperscriptOption{WithStats()},
+			md:   "x^2^ and E=mc^2\n\n^a^ and y^^\n\nz^a b^",
+			html: "<p>x<sup>2</sup> and E=mc^2</p>\n<p>^a^ and y^^</p>\n<p>z^a b^</p>\n",
+			want: map[string]int{
+				RejectUnclosed:          3,
+				RejectLeadingWhitespace: 1,
+				RejectDelimiter:         1,
+				RejectWhitespace:        2,
+			},
+		},
+		{
+			desc: "Stats: rejections by options",
+			opts: []SuperscriptOption{WithStats(), WithRequireNonNumeric(), WithRespectFootnotes()},
+			md:   "x^2^ and y^n^ and [^1^]",
+			html: "<p>x^2^ and y<sup>n</sup> and [^1^]</p>\n",
+			want: map[string]int{
+				RejectContent:    1,
+				RejectContext:    1,
+				RejectWhitespace: 1,
+				RejectUnclosed:   1,
+			},
+		},
+		{
+			desc: "Stats: nothing counted without the option",
+			md:   "E=mc^2",
+			html: "<p>E=mc^2</p>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			md := goldmark.New(goldmark.WithExtensions(NewSuperscript(tc.opts...)))
+			pc := parser.NewContext()
+			var buf bytes.Buffer
+			if err := md.Convert([]byte(tc.md), &buf, parser.WithContext(pc)); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tc.html {
+				t.Errorf("html = %q, want %q", buf.String(), tc.html)
+			}
+			if got := Stats(pc); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Stats() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package superscript
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// strayCaretTransformer encodes the carets left in text nodes for WithEncodeStrayCarets.
+//
+// A caret that does not start or end a superscript is never turned into a node by the
+// parser; it simply stays part of the surrounding text. The transformer therefore runs
+// after parsing, walks the text nodes outside code spans and superscripts, and splits
+// each one around its carets, putting a string node with the character reference in
+// place of every caret.
+type strayCaretTransformer struct{}
+
+// NewStrayCaretTransformer returns the AST transformer that encodes stray carets for
+// WithEncodeStrayCarets. It should run after the other superscript transformers, once
+// every caret that opens or closes a superscript has been consumed.
+func NewStrayCaretTransformer() parser.ASTTransformer {
+	return strayCaretTransformer{}
+}
+
+// caretReference is the character reference written for a stray caret.
+var caretReference = []byte("&#94;")
+
+// Transform implements parser.ASTTransformer.
+func (strayCaretTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan, KindSuperscript:
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			if t := n.(*ast.Text); bytes.IndexByte(t.Segment.Value(source), '^') != -1 {
+				texts = append(texts, t)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, t := range texts {
+		encodeCarets(t, source)
+	}
+}
+
+// encodeCarets replaces t with text nodes for the runs between its carets and string
+// nodes holding caretReference for the carets. A backslash escaping a caret is dropped.
+func encodeCarets(t *ast.Text, source []byte) {
+	parent := t.Parent()
+	seg := t.Segment
+	for {
+		i := bytes.IndexByte(seg.Value(source), '^')
+		if i == -1 {
+			break
+		}
+		stop := seg.Start + i
+		if i > 0 && source[stop-1] == '\\' {
+			stop--
+		}
+		if stop > seg.Start {
+			before := ast.NewTextSegment(text.NewSegment(seg.Start, stop))
+			before.SetRaw(t.IsRaw())
+			parent.InsertBefore(parent, t, before)
+		}
+		caret := ast.NewString(caretReference)
+		caret.SetCode(true)
+		parent.InsertBefore(parent, t, caret)
+		seg = seg.WithStart(seg.Start + i + 1)
+	}
+	if seg.Len() > 0 || t.SoftLineBreak() || t.HardLineBreak() {
+		t.Segment = seg
+		return
+	}
+	parent.RemoveChild(parent, t)
+}
@@ -0,0 +1,57 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestSuperscriptEncodeStrayCarets(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithEncodeStrayCarets()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Stray carets: unmatched caret is encoded",
+			md:   `E=mc^2 and x^2^`,
+			html: `<p>E=mc&#94;2 and x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Stray carets: several in one text and across lines",
+			md:   "a ^ b ^^\nc^",
+			html: "<p>a &#94; b &#94;&#94;\nc&#94;</p>",
+		},
+		{
+			desc: "Stray carets: escaped caret",
+			md:   `a\^b`,
+			html: `<p>a&#94;b</p>`,
+		},
+		{
+			desc: "Stray carets: inside emphasis",
+			md:   `*a ^ b*`,
+			html: `<p><em>a &#94; b</em></p>`,
+		},
+		{
+			desc: "Stray carets: code spans are left alone",
+			md:   "`a^b` and c^",
+			html: `<p><code>a^b</code> and c&#94;</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Stray carets: default leaves them as text",
+			md:   `E=mc^2`,
+			html: `<p>E=mc^2</p>`,
+		},
+	})
+}
@@ -14,10 +14,21 @@
 //   - Superscripts must not start at the beginning of a line or after whitespace
 //   - Content between carets cannot contain spaces or additional carets
 //   - Empty superscripts (^^ with no content) are not parsed as superscripts
+//
+// Some options also need an AST transformer, which NewSuperscript registers along with
+// the parser and renderer. When those are registered by hand instead, the transformers
+// returned by the New...Transformer functions must be registered for such options too.
 package superscript
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
@@ -34,6 +45,18 @@ var KindSuperscript = ast.NewNodeKind("Superscript")
 // Node represents a superscript node in the AST.
 type Node struct {
 	ast.BaseInline
+
+	// span covers the superscript in the source, delimiters included. It is empty for
+	// nodes that were not created by the parser.
+	span text.Segment
+
+	// text is storage for the content child, so the parser can allocate the node and
+	// its content together.
+	text ast.Text
+
+	// seq is the position of the node among the document's superscripts, starting at
+	// 1, when WithAutoID or WithFirstOccurrence is set. It is 0 otherwise.
+	seq int
 }
 
 // Kind implements ast.Node.Kind and returns the node kind for superscript nodes.
@@ -41,9 +64,12 @@ func (*Node) Kind() ast.NodeKind {
 	return KindSuperscript
 }
 
-// Dump implements ast.Node.Dump and prints the node structure for debugging.
+// Dump implements ast.Node.Dump and prints the node structure for debugging, including
+// the superscript's content.
 func (n *Node) Dump(source []byte, level int) {
-	ast.DumpHelper(n, source, level, nil, nil)
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Content": string(nodeContent(n, source)),
+	}, nil)
 }
 
 // NewSuperscriptNode returns a new Superscript node.
@@ -51,20 +77,72 @@ func NewSuperscriptNode() *Node {
 	return &Node{}
 }
 
+// nodePool holds released superscript nodes for reuse when WithNodePool is set.
+var nodePool = sync.Pool{
+	New: func() any {
+		return &Node{}
+	},
+}
+
+// Release returns every superscript node under doc to the pool used by WithNodePool.
+//
+// The parser cannot know when a document is no longer needed, so pooling only pays off
+// when the caller releases each document once it has been rendered. After Release the
+// document and its superscript nodes must not be used again. Calling Release is
+// optional; unreleased nodes are garbage collected as usual.
+func Release(doc ast.Node) {
+	var nodes []*Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if sup, ok := n.(*Node); ok && entering {
+			nodes = append(nodes, sup)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range nodes {
+		*n = Node{}
+		nodePool.Put(n)
+	}
+}
+
 // superscriptParser implements parser.InlineParser for superscript syntax.
 type superscriptParser struct {
+	cfg Config
 }
 
 var defaultSuperscriptParser = &superscriptParser{}
 
-// NewSuperscriptParser returns a new InlineParser that parses superscript expressions.
-func NewSuperscriptParser() parser.InlineParser {
-	return defaultSuperscriptParser
+// NewSuperscriptParser returns a new InlineParser that parses superscript expressions
+// with the parser settings of opts. Without options it returns a shared parser with the
+// default settings.
+func NewSuperscriptParser(opts ...SuperscriptOption) parser.InlineParser {
+	if len(opts) == 0 {
+		return defaultSuperscriptParser
+	}
+	return NewSuperscriptParserWithConfig(NewConfig(opts...))
+}
+
+// NewSuperscriptParserWithConfig returns a new InlineParser that parses superscript
+// expressions using the parser settings in cfg. Together with
+// NewSuperscriptHTMLRendererWithConfig it lets one Config be shared by a parser and
+// renderer registered without the extension.
+func NewSuperscriptParserWithConfig(cfg *Config) parser.InlineParser {
+	return &superscriptParser{cfg: *cfg}
 }
 
 // Trigger implements parser.InlineParser.Trigger.
 func (s *superscriptParser) Trigger() []byte {
-	return []byte{'^'}
+	if len(s.cfg.delimiters) == 0 {
+		return []byte{'^'}
+	}
+	// goldmark only triggers inline parsers on ASCII punctuation, so other delimiters
+	// are matched by the transformer returned by NewDelimiterTransformer
+	var trigger []byte
+	for _, r := range s.cfg.delimiters {
+		if r < utf8.RuneSelf {
+			trigger = append(trigger, byte(r))
+		}
+	}
+	return trigger
 }
 
 // Parse implements parser.InlineParser.Parse and parses superscript expressions.
@@ -74,95 +152,954 @@ func (s *superscriptParser) Trigger() []byte {
 //   - Content between carets cannot contain spaces or additional carets
 //   - Empty superscripts (^^) are not parsed as superscripts
 func (s *superscriptParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
-	before := block.PrecendingCharacter()
+	if s.disallowedParent(parent) || (parent != nil && insideRawSup(parent, block.Source(), pc)) {
+		return s.reject(pc, RejectContext)
+	}
+	if _, segment := block.PeekLine(); s.atLimit(pc, segment.Start) {
+		return s.reject(pc, RejectLimit)
+	}
+	return s.counted(pc, s.parse(parent, block, pc))
+}
+
+// disallowedParent reports whether parent or one of its ancestors is of a kind given
+// to WithDisallowedParents.
+func (s *superscriptParser) disallowedParent(parent ast.Node) bool {
+	if len(s.cfg.disallowedParents) == 0 {
+		return false
+	}
+	for n := parent; n != nil; n = n.Parent() {
+		for _, kind := range s.cfg.disallowedParents {
+			if n.Kind() == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rawSupKey is the parser.Context key under which the raw <sup> depth of the inline
+// nodes parsed so far is kept, so that each caret only reads the nodes added since the
+// previous one.
+var rawSupKey = parser.NewContextKey()
+
+// rawSupDepth is the number of raw <sup> elements left open by the children of parent
+// up to and including last.
+type rawSupDepth struct {
+	parent ast.Node
+	last   ast.Node
+	depth  int
+}
+
+// add counts the raw <sup> and </sup> tags of the inline node n, which follows the
+// nodes already counted. A closing tag without an opening one is ignored.
+func (d *rawSupDepth) add(n ast.Node, source []byte) {
+	d.last = n
+	raw, ok := n.(*ast.RawHTML)
+	if !ok {
+		return
+	}
+	var tag []byte
+	for i := 0; i < raw.Segments.Len(); i++ {
+		segment := raw.Segments.At(i)
+		tag = append(tag, segment.Value(source)...)
+	}
+	switch {
+	case isTag(tag, "</sup"):
+		if d.depth > 0 {
+			d.depth--
+		}
+	case isTag(tag, "<sup"):
+		d.depth++
+	}
+}
+
+// insideRawSup reports whether a caret after the last child of parent is inside a
+// <sup> element written as raw HTML, as in x<sup>^2^</sup>, which already marks its
+// content as a superscript. The depth is carried over in pc while the same parent is
+// being parsed; it is counted again from the first child when the parent changes or
+// the last counted node has been moved, as a link does with the nodes of its label.
+func insideRawSup(parent ast.Node, source []byte, pc parser.Context) bool {
+	d, _ := pc.Get(rawSupKey).(*rawSupDepth)
+	if d == nil {
+		d = &rawSupDepth{}
+		pc.Set(rawSupKey, d)
+	}
+	n := parent.FirstChild()
+	if d.parent == parent && d.last != nil && d.last.Parent() == parent {
+		n = d.last.NextSibling()
+	} else {
+		*d = rawSupDepth{parent: parent}
+	}
+	for ; n != nil; n = n.NextSibling() {
+		d.add(n, source)
+	}
+	return d.depth > 0
+}
+
+// isTag reports whether tag starts with prefix, compared case-insensitively, followed by
+// the end of the tag name.
+func isTag(tag []byte, prefix string) bool {
+	if len(tag) <= len(prefix) || !bytes.EqualFold(tag[:len(prefix)], []byte(prefix)) {
+		return false
+	}
+	next := tag[len(prefix)]
+	return next == '>' || next == '/' || unicode.IsSpace(rune(next))
+}
+
+// parse implements Parse.
+func (s *superscriptParser) parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	// Delimiters other than the caret set by WithDelimiters have their own rules
+	if line, _ := block.PeekLine(); len(line) > 0 && line[0] != '^' {
+		return s.parseAlternate(block, pc, rune(line[0]))
+	}
+
+	if s.cfg.symmetricOnly && oddCarets(parent, block.Source(), pc) {
+		return s.reject(pc, RejectUnpaired)
+	}
+
 	line, segment := block.PeekLine()
 
 	// Check if we have at least one character after the caret
 	if len(line) < 2 {
-		return nil
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, RejectUnclosed)
 	}
 
-	// If preceded by whitespace or is first character of line, not a superscript
-	if unicode.IsSpace(before) || before == -1 {
-		return nil
+	// A doubled caret opens the explicit ^^...^^ form when it is enabled
+	if s.cfg.doubleCaret && line[1] == '^' {
+		return s.parseDoubleCaret(block, pc)
+	}
+	if s.cfg.doubleCaretOnly {
+		return s.reject(pc, RejectDelimiter)
 	}
 
-	// If we have two carets in sequence, this should be handled by strikethrough
-	if len(line) >= 2 && line[1] == '^' {
-		return nil
+	// The second caret of a rejected ^^ opener must not open a single-caret superscript.
+	// A caret that closed the previous superscript is fine, as in a^2^^2^.
+	if s.cfg.doubleCaret && block.PrecendingCharacter() == '^' {
+		if _, ok := parent.LastChild().(*Node); !ok {
+			return s.reject(pc, RejectDelimiter)
+		}
+	}
+
+	if s.cfg.isotopeMode {
+		if node := s.parseIsotope(block); node != nil {
+			return node
+		}
+	}
+
+	if s.cfg.tower {
+		if node := s.parseTower(block); node != nil {
+			return node
+		}
 	}
 
 	// Find the content between carets
 	start := 1 // Skip the opening caret
-	end := -1
 
-	// Look for the closing caret
-	for i := start; i < len(line); i++ {
-		if line[i] == '^' {
-			end = i
-			break
-		}
+	// Without strikethrough deference a caret right after the opening one is content,
+	// so the closing delimiter is looked for after it
+	from := start
+	if s.cfg.noStrikethroughDeference && line[1] == '^' {
+		from++
 	}
 
-	// If no closing caret found on this line, not a superscript
-	if end == -1 {
-		return nil
+	// Look for the closing caret. Most carets in prose are never closed, so this is
+	// checked first to keep the common rejection path to a single IndexByte scan.
+	closer, closerLen := s.cfg.closer()
+	var end int
+	if closer == '^' {
+		end = bytes.IndexByte(line[from:], '^')
+	} else {
+		end = bytes.IndexRune(line[from:], closer)
+	}
+
+	// If no closing caret found on this line, not a superscript. Multi-line content is
+	// looked for on the following lines once the opening caret has been validated.
+	closed := end != -1
+	if !closed && !s.cfg.multiline && !(s.cfg.lineContinuation && continuesLine(line)) {
+		return s.parseImplicit(block, pc, RejectUnclosed)
+	}
+	end += from
+
+	// Optionally treat decorative runs of three or more carets as literal text
+	if s.cfg.literalTripleCaret && caretRun(block.Source(), segment.Start) >= 3 {
+		return s.reject(pc, RejectDelimiter)
+	}
+
+	// If we have two carets in sequence, this should be handled by strikethrough
+	if line[1] == '^' && !s.cfg.noStrikethroughDeference {
+		return s.reject(pc, RejectDelimiter)
+	}
+
+	if reason := s.canOpen(block); reason != "" {
+		return s.reject(pc, reason)
+	}
+
+	if !closed {
+		return s.parseMultiline(block, pc)
 	}
 
 	// Check if there's any content between carets
 	if end <= start {
-		return nil
+		return s.reject(pc, RejectEmpty)
 	}
 
 	content := line[start:end]
 
-	// Check if content has any whitespace (not allowed in superscript)
-	for _, b := range content {
-		if unicode.IsSpace(rune(b)) {
-			return nil
+	// A closing caret that starts a run of three or more is literal too
+	if s.cfg.literalTripleCaret && closer == '^' && caretRun(block.Source(), segment.Start+end) >= 3 {
+		return s.reject(pc, RejectDelimiter)
+	}
+
+	// Optionally allow spaces in content wrapped in double quotes, which are not rendered
+	quoted := s.cfg.quotedContent && isQuoted(content)
+
+	// Check if content has any whitespace (not allowed in superscript). The caret that
+	// was found belongs to a later superscript, so this one is unmatched. The '\r' of a
+	// CRLF line ending is whitespace too, so it can never end up inside the content.
+	for _, r := range string(content) {
+		if s.cfg.isSpace(r) && !(quoted && r == ' ') {
+			return s.parseImplicit(block, pc, RejectWhitespace)
 		}
 	}
 
 	// Check first character requirements: allow any non-whitespace character except caret
 	firstChar := rune(content[0])
-	if firstChar == '^' {
-		return nil
+	if firstChar == '^' && !s.cfg.noStrikethroughDeference {
+		return s.reject(pc, RejectDelimiter)
+	}
+
+	// With an alternate closing delimiter the content can still contain an opening caret
+	if closer != '^' && bytes.IndexByte(content, '^') != -1 {
+		return s.reject(pc, RejectDelimiter)
+	}
+
+	// A superscript must not cross the boundary of a link, as in [a^b](c^d): the
+	// brackets belong to the link, which is parsed once the label is closed. Nor may it
+	// cross a code span, as in a^b`^`, whose backticks take precedence.
+	if crossesLink(block, content, line[end+closerLen:]) || crossesCodeSpan(line, start, end) {
+		return s.reject(pc, RejectContext)
 	}
 
 	// All subsequent characters are allowed except caret (handled by finding closing caret above)
-	// No additional character validation needed since whitespace is already checked above
+	// Any further restrictions come from the configured options
+	contentStart, contentEnd := start, end
+	if quoted {
+		contentStart, contentEnd = start+1, end-1
+	}
+	if !s.acceptContent(line[contentStart:contentEnd]) {
+		return s.reject(pc, RejectContent)
+	}
+
+	// Create the superscript node, which also holds storage for its text child
+	node := s.newNode()
 
-	// Create the superscript node
-	node := NewSuperscriptNode()
+	// Record the full source span, delimiters included
+	node.span = text.NewSegment(segment.Start, segment.Start+end+closerLen)
 
 	// Advance past the opening caret
 	block.Advance(1)
 
-	// Parse the content inside - create a text segment for the content
-	tempSegment := segment.WithStart(segment.Start + start)
-	contentSegment := tempSegment.WithStop(segment.Start + end)
-	node.AppendChild(node, ast.NewTextSegment(contentSegment))
+	// Optionally move decorative trailing punctuation out of the superscript
+	textEnd := contentEnd
+	if s.cfg.trimTrailingPunctuation && !quoted && !endsWithReference(content) {
+		textEnd = start + len(bytes.TrimRight(content, trailingPunctuation))
+		if textEnd == start {
+			textEnd = end
+		}
+	}
+
+	// Parse the content inside - point the text child at the content segment
+	node.text.Segment = text.NewSegmentPadding(segment.Start+contentStart, segment.Start+textEnd, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
+
+	// Advance past the content and closing delimiter
+	block.Advance(end - start + closerLen)
+
+	// The trimmed punctuation follows the superscript as a sibling text node. Parse can
+	// only return one node, so the superscript is appended to the parent here and the
+	// punctuation is returned for the inline parser to append after it.
+	if textEnd != contentEnd {
+		parent.AppendChild(parent, node)
+		return ast.NewTextSegment(text.NewSegment(segment.Start+textEnd, segment.Start+end))
+	}
+
+	return node
+}
+
+// parseImplicit handles an opening caret without a closing delimiter. With
+// WithImplicitClose or WithCloseOnBoundary the superscript takes the run of matching
+// runes after the caret; otherwise, or when there is no such run, the caret is reported
+// as unmatched and counted as rejected for reason.
+func (s *superscriptParser) parseImplicit(block text.Reader, pc parser.Context, reason string) ast.Node {
+	line, segment := block.PeekLine()
+	if s.cfg.parenImplicitClose && line[1] == '(' {
+		if node := s.parseParenGroup(block, pc); node != nil {
+			return node
+		}
+	}
+	if s.cfg.closeAtParagraphEnd && reason == RejectUnclosed {
+		if node := s.parseParagraphEnd(block); node != nil {
+			return node
+		}
+	}
+	if s.cfg.implicitClose == nil || line[1] == '^' ||
+		(s.cfg.literalTripleCaret && caretRun(block.Source(), segment.Start) >= 3) {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, reason)
+	}
+	if openReason := s.canOpen(block); openReason != "" {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, openReason)
+	}
 
-	// Advance past the content and closing caret
+	end := 1
+	for end < len(line) {
+		r, size := utf8.DecodeRune(line[end:])
+		if r == '^' || s.cfg.isSpace(r) || !s.cfg.implicitClose(r) {
+			break
+		}
+		end += size
+	}
+	// WithCloseOnBoundary only accepts a run that is not followed by a word character
+	if s.cfg.implicitBoundary && end < len(line) {
+		if r, _ := utf8.DecodeRune(line[end:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			s.reportUnmatched(block, pc, segment.Start)
+			return s.reject(pc, reason)
+		}
+	}
+	// The default class of WithCloseOnBoundary only raises charges, which end in a sign
+	if end == 1 || (s.cfg.implicitCharge && line[end-1] != '+' && line[end-1] != '-') {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, reason)
+	}
+	if !s.acceptContent(line[1:end]) {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, RejectContent)
+	}
+
+	node := s.newNode()
+	node.span = text.NewSegment(segment.Start, segment.Start+end)
+	node.text.Segment = text.NewSegmentPadding(segment.Start+1, segment.Start+end, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
 	block.Advance(end)
+	return node
+}
 
+// parseParenGroup parses a superscript whose content is the balanced parenthesized
+// group right after the caret, as in x^(n+1), for WithParenImplicitClose. The group may
+// nest but not contain whitespace or carets. It returns nil, without advancing the
+// reader, when there is no such group.
+func (s *superscriptParser) parseParenGroup(block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	depth, end := 0, -1
+	for i, size := 1, 0; i < len(line) && end == -1; i += size {
+		var r rune
+		r, size = utf8.DecodeRune(line[i:])
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth == 0 {
+				end = i + 1
+			}
+		case r == '^' || s.cfg.isSpace(r):
+			return nil
+		}
+	}
+	if end == -1 || s.canOpen(block) != "" {
+		return nil
+	}
+	contentStart, contentEnd := 1, end
+	if !s.cfg.keepParens {
+		contentStart, contentEnd = 2, end-1
+	}
+	if contentEnd == contentStart || !s.acceptContent(line[contentStart:contentEnd]) {
+		return nil
+	}
+
+	node := s.newNode()
+	node.span = text.NewSegment(segment.Start, segment.Start+end)
+	node.text.Segment = text.NewSegmentPadding(segment.Start+contentStart, segment.Start+contentEnd, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
+	block.Advance(end)
+	return node
+}
+
+// parseParagraphEnd parses a superscript left unclosed at the end of a paragraph, as in
+// "the answer is x^2", for WithCloseAtParagraphEnd. The content is the rest of the
+// paragraph's last line, which must not contain whitespace or carets. It returns nil,
+// without advancing the reader, when the caret is not on the last line or the content
+// is not acceptable.
+func (s *superscriptParser) parseParagraphEnd(block text.Reader) ast.Node {
+	line, segment := block.PeekLine()
+	content := bytes.TrimRight(line[1:], "\r\n")
+	if len(content) == 0 || bytes.IndexByte(content, '^') != -1 {
+		return nil
+	}
+	if s.cfg.containsSpace(content) {
+		return nil
+	}
+	if s.canOpen(block) != "" || !s.acceptContent(content) {
+		return nil
+	}
+
+	// Only the last line of the paragraph has no line after it
+	savedLine, savedPosition := block.Position()
+	block.AdvanceLine()
+	next, _ := block.PeekLine()
+	block.SetPosition(savedLine, savedPosition)
+	if next != nil {
+		return nil
+	}
+
+	end := 1 + len(content)
+	node := s.newNode()
+	node.span = text.NewSegment(segment.Start, segment.Start+end)
+	node.text.Segment = text.NewSegmentPadding(segment.Start+1, segment.Start+end, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
+	block.Advance(end)
+	return node
+}
+
+// canOpen reports why the caret at the reader's position may not open a superscript,
+// judging by the text before it, or returns "" if it may.
+func (s *superscriptParser) canOpen(block text.Reader) string {
+	// If preceded by whitespace or is first character of line, not a superscript
+	before := block.PrecendingCharacter()
+	if unicode.IsSpace(before) || before == -1 {
+		return RejectLeadingWhitespace
+	}
+
+	// Optionally require a word character rather than any non-whitespace before the caret
+	if s.cfg.requireWordBefore && !unicode.IsLetter(before) && !unicode.IsDigit(before) {
+		return RejectContext
+	}
+	return s.literalContext(block)
+}
+
+// literalContext reports why the surroundings of the caret at the reader's position
+// keep it literal whatever character precedes it, or returns "" if nothing does.
+func (s *superscriptParser) literalContext(block text.Reader) string {
+	before := block.PrecendingCharacter()
+
+	// A caret right after an opening bracket starts a footnote reference ([^id])
+	if s.cfg.respectFootnotes && before == '[' {
+		return RejectContext
+	}
+
+	// LaTeX uses carets natively, so leave them alone inside $...$ math spans
+	if s.cfg.skipInMath && insideMath(block) {
+		return RejectContext
+	}
+
+	// A caret between CJK characters is part of the text, not a superscript
+	if s.cfg.skipCJKContext && isCJK(before) {
+		line, _ := block.PeekLine()
+		first, _ := utf8.DecodeRune(bytes.TrimLeft(line, "^"))
+		if isCJK(first) {
+			return RejectContext
+		}
+	}
+	return ""
+}
+
+// isCJK reports whether r is a Han, Hiragana or Katakana character.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// doubleCaret is the opening and closing delimiter of the explicit form enabled by
+// WithDoubleCaret.
+var doubleCaret = []byte("^^")
+
+// parseDoubleCaret parses an explicit ^^content^^ superscript at the reader's position.
+// Single carets are ordinary content here; whitespace is still not allowed.
+func (s *superscriptParser) parseDoubleCaret(block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	start := len(doubleCaret)
+	end := bytes.Index(line[start:], doubleCaret)
+	if end == -1 {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, RejectUnclosed)
+	}
+	end += start
+
+	if s.cfg.literalTripleCaret && caretRun(block.Source(), segment.Start) >= 3 {
+		return s.reject(pc, RejectDelimiter)
+	}
+	if reason := s.canOpen(block); reason != "" {
+		return s.reject(pc, reason)
+	}
+
+	content := line[start:end]
+	if len(content) == 0 {
+		return s.reject(pc, RejectEmpty)
+	}
+	if content[0] == '^' {
+		return s.reject(pc, RejectDelimiter)
+	}
+	if s.cfg.containsSpace(content) {
+		s.reportUnmatched(block, pc, segment.Start)
+		return s.reject(pc, RejectWhitespace)
+	}
+	if crossesLink(block, content, line[end+len(doubleCaret):]) || crossesCodeSpan(line, start, end) {
+		return s.reject(pc, RejectContext)
+	}
+	if !s.acceptContent(content) {
+		return s.reject(pc, RejectContent)
+	}
+
+	node := s.newNode()
+	node.span = text.NewSegment(segment.Start, segment.Start+end+len(doubleCaret))
+	node.text.Segment = text.NewSegmentPadding(segment.Start+start, segment.Start+end, segment.Padding)
+	node.AppendChild(node, &node.text)
+	s.normalize(node, block.Source())
+	block.Advance(end + len(doubleCaret))
 	return node
 }
 
+// parseTower parses an exponent tower such as 2^2^2^ at the reader's position for
+// WithTower, nesting one superscript per caret-separated level. It returns nil, without
+// advancing the reader, when there is no chain of at least two levels there, leaving
+// the caret to the single-level rules.
+func (s *superscriptParser) parseTower(block text.Reader) ast.Node {
+	if closer, _ := s.cfg.closer(); closer != '^' {
+		return nil
+	}
+	line, segment := block.PeekLine()
+
+	// Collect the carets of the chain, which ends at the last caret before whitespace
+	carets := []int{0}
+	for i, size := 1, 0; i < len(line); i += size {
+		var r rune
+		if r, size = utf8.DecodeRune(line[i:]); s.cfg.isSpace(r) {
+			break
+		}
+		if r == '^' {
+			if i == carets[len(carets)-1]+1 {
+				return nil
+			}
+			carets = append(carets, i)
+		}
+	}
+	// A caret run such as 2^^2^2^ is not the start of a chain either
+	if len(carets) < 3 || block.PrecendingCharacter() == '^' || s.canOpen(block) != "" {
+		return nil
+	}
+	end := carets[len(carets)-1]
+	if crossesLink(block, line[:end], line[end+1:]) || crossesCodeSpan(line, 0, end) {
+		return nil
+	}
+	for i := 0; i < len(carets)-1; i++ {
+		if !s.acceptContent(line[carets[i]+1 : carets[i+1]]) {
+			return nil
+		}
+	}
+
+	// Each level holds its own content followed by the next level
+	var root, outer *Node
+	for i := 0; i < len(carets)-1; i++ {
+		node := s.newNode()
+		node.span = text.NewSegment(segment.Start+carets[i], segment.Start+end+1)
+		node.text.Segment = text.NewSegmentPadding(segment.Start+carets[i]+1, segment.Start+carets[i+1], segment.Padding)
+		node.AppendChild(node, &node.text)
+		s.normalize(node, block.Source())
+		if outer == nil {
+			root = node
+		} else {
+			outer.AppendChild(outer, node)
+		}
+		outer = node
+	}
+	block.Advance(end + 1)
+	return root
+}
+
+// trailingPunctuation lists the characters moved outside the superscript by
+// WithTrimTrailingPunctuation. Exclamation marks are not included so that factorials
+// such as n^2!^ keep their meaning.
+const trailingPunctuation = ".,;:"
+
+// isQuoted reports whether content is non-blank text wrapped in a pair of straight
+// double quotes, with no other double quote inside.
+func isQuoted(content []byte) bool {
+	if len(content) < 3 || content[0] != '"' || content[len(content)-1] != '"' {
+		return false
+	}
+	inner := content[1 : len(content)-1]
+	return bytes.IndexByte(inner, '"') == -1 && len(bytes.TrimSpace(inner)) > 0
+}
+
+// crossesLink reports whether a superscript with content, followed by the text after,
+// would cross the boundary of a link label: content closes a square bracket opened
+// before the caret at the reader's position, or opens one that is closed right after
+// it. Brackets escaped with a backslash are ignored.
+func crossesLink(block text.Reader, content, after []byte) bool {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth < 0 {
+				return openBracketBefore(block)
+			}
+		}
+	}
+	return depth > 0 && len(after) > 0 && after[0] == ']'
+}
+
+// crossesCodeSpan reports whether a backtick run in line[start:end], the content of a
+// superscript, opens a code span that is closed at or after end. Backtick runs that are
+// closed within the content, or never closed on the line, are left to the code span
+// parser as they are.
+func crossesCodeSpan(line []byte, start, end int) bool {
+	for i := start; i < end; i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '`':
+			n := backtickRun(line, i)
+			j := skipCodeSpan(line, i)
+			if j >= end && j != i+n-1 {
+				return true
+			}
+			i = j
+		}
+	}
+	return false
+}
+
+// openBracketBefore reports whether the current source line has a square bracket
+// before the reader's position that is not closed before it. Escaped brackets and
+// brackets inside code spans are ignored.
+func openBracketBefore(block text.Reader) bool {
+	source := block.Source()
+	_, pos := block.Position()
+	before := source[bytes.LastIndexByte(source[:pos.Start], '\n')+1 : pos.Start]
+	depth := 0
+	for i := 0; i < len(before); i++ {
+		switch before[i] {
+		case '\\':
+			i++
+		case '`':
+			i = skipCodeSpan(before, i)
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return depth > 0
+}
+
+// resolveReferences returns content with its named and numeric character references
+// replaced by the characters they stand for.
+func resolveReferences(content []byte) []byte {
+	if bytes.IndexByte(content, '&') == -1 {
+		return content
+	}
+	return util.ResolveEntityNames(util.ResolveNumericReferences(content))
+}
+
+// endsWithReference reports whether content ends with a character reference such as
+// &deg; whose semicolon must not be mistaken for punctuation.
+func endsWithReference(content []byte) bool {
+	i := bytes.LastIndexByte(content, '&')
+	if i == -1 || content[len(content)-1] != ';' {
+		return false
+	}
+	reference := content[i:]
+	return !bytes.Equal(resolveReferences(reference), reference)
+}
+
+// caretRun returns the length of the run of consecutive carets containing source[i].
+func caretRun(source []byte, i int) int {
+	start, stop := i, i
+	for start > 0 && source[start-1] == '^' {
+		start--
+	}
+	for stop < len(source) && source[stop] == '^' {
+		stop++
+	}
+	return stop - start
+}
+
+// acceptContent applies the content restrictions configured by options to the
+// content of an otherwise valid superscript. Character references are resolved first,
+// so that &deg; counts as the one character it renders as.
+func (s *superscriptParser) acceptContent(content []byte) bool {
+	if s.cfg.rejectInvalidUTF8 && !utf8.Valid(content) {
+		return false
+	}
+	content = resolveReferences(content)
+	if s.cfg.rejectZeroWidth && bytes.IndexFunc(content, isZeroWidth) != -1 {
+		return false
+	}
+	if s.cfg.trimZeroWidth {
+		content = stripZeroWidth(content)
+		if len(content) == 0 {
+			return false
+		}
+	}
+	if s.cfg.requireNonNumeric && isNumeric(content) {
+		return false
+	}
+	if s.cfg.minContentLength > 1 && utf8.RuneCount(content) < s.cfg.minContentLength {
+		return false
+	}
+	if s.cfg.allowedRunes != nil {
+		for _, r := range string(content) {
+			if !s.cfg.allowedRunes(r) {
+				return false
+			}
+		}
+	}
+	if len(s.cfg.allowedScripts) > 0 {
+		for _, r := range string(content) {
+			if !unicode.IsOneOf(s.cfg.allowedScripts, r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether content consists only of digits.
+func isNumeric(content []byte) bool {
+	for _, r := range string(content) {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize replaces the content of node with the same content without zero-width
+// runes when WithTrimZeroWidth is set, and in its normalized form when WithNormalize is
+// set. The content is left alone when neither changes it.
+func (s *superscriptParser) normalize(node *Node, source []byte) {
+	if !s.cfg.normalize && !s.cfg.trimZeroWidth {
+		return
+	}
+	content := nodeContent(node, source)
+	normalized := content
+	if s.cfg.trimZeroWidth {
+		normalized = stripZeroWidth(normalized)
+	}
+	if s.cfg.normalize && !s.cfg.normalizeForm.IsNormal(normalized) {
+		normalized = s.cfg.normalizeForm.Bytes(normalized)
+	}
+	if bytes.Equal(normalized, content) {
+		return
+	}
+	node.RemoveChildren(node)
+	node.AppendChild(node, ast.NewString(normalized))
+}
+
+// isZeroWidth reports whether r is an invisible zero-width rune: a zero-width space,
+// non-joiner or joiner, a word joiner, or a byte order mark.
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u200B', '\u200C', '\u200D', '\u2060', '\uFEFF':
+		return true
+	}
+	return false
+}
+
+// stripZeroWidth returns content without its zero-width runes. Content without any is
+// returned as is.
+func stripZeroWidth(content []byte) []byte {
+	if bytes.IndexFunc(content, isZeroWidth) == -1 {
+		return content
+	}
+	return bytes.Map(func(r rune) rune {
+		if isZeroWidth(r) {
+			return -1
+		}
+		return r
+	}, content)
+}
+
+// newNode returns an empty superscript node, taken from the pool when enabled.
+func (s *superscriptParser) newNode() *Node {
+	if s.cfg.nodePool {
+		return nodePool.Get().(*Node)
+	}
+	return NewSuperscriptNode()
+}
+
+// parseMultiline parses a superscript whose closing delimiter is on a later line of the
+// paragraph. The content may continue through any number of soft line breaks, each of
+// which is replaced by the configured separator. On failure the reader is restored.
+func (s *superscriptParser) parseMultiline(block text.Reader, pc parser.Context) ast.Node {
+	closer, closerLen := s.cfg.closer()
+	savedLine, savedPosition := block.Position()
+	node := s.newNode()
+
+	block.Advance(1) // Skip the opening caret
+
+	// joined reports whether the previous line ended in a line continuation
+	joined := false
+	for {
+		line, segment := block.PeekLine()
+		if line == nil {
+			break
+		}
+		end := bytes.IndexRune(line, closer)
+		part := line
+		if end != -1 {
+			part = line[:end]
+		} else {
+			part = bytes.TrimSuffix(bytes.TrimSuffix(part, []byte{'\n'}), []byte{'\r'})
+		}
+		// With WithLineContinuation a trailing backslash joins the next line directly
+		continues := s.cfg.lineContinuation && end == -1 && continuesLine(part)
+		if continues {
+			part = part[:len(part)-1]
+		}
+		// Otherwise lines must be joined by soft line breaks, so an empty first line or
+		// a trailing backslash (a hard line break) ends the search.
+		if !s.validMultilinePart(part, closer) || (end == -1 && !continues &&
+			(!s.cfg.multiline || len(part) == 0 || part[len(part)-1] == '\\')) {
+			break
+		}
+		if node.HasChildren() && !joined && s.cfg.multilineSeparator != "" {
+			node.AppendChild(node, ast.NewString([]byte(s.cfg.multilineSeparator)))
+		}
+		joined = continues
+		if len(part) > 0 {
+			node.AppendChild(node, ast.NewTextSegment(segment.WithStop(segment.Start+len(part))))
+		}
+		if end != -1 {
+			if !node.HasChildren() || !s.acceptContent(nodeContent(node, block.Source())) {
+				break
+			}
+			node.span = text.NewSegment(savedPosition.Start, segment.Start+end+closerLen)
+			block.Advance(end + closerLen)
+			s.normalize(node, block.Source())
+			return node
+		}
+		block.AdvanceLine()
+	}
+
+	block.SetPosition(savedLine, savedPosition)
+	s.reportUnmatched(block, pc, savedPosition.Start)
+	return s.reject(pc, RejectUnclosed)
+}
+
+// continuesLine reports whether line ends in a backslash right before its line ending,
+// the line continuation of WithLineContinuation.
+func continuesLine(line []byte) bool {
+	line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte{'\n'}), []byte{'\r'})
+	return len(line) > 0 && line[len(line)-1] == '\\'
+}
+
+// validMultilinePart reports whether part of a multi-line superscript is free of
+// whitespace and stray opening carets.
+func (s *superscriptParser) validMultilinePart(part []byte, closer rune) bool {
+	for _, r := range string(part) {
+		if s.cfg.isSpace(r) || (r == '^' && closer != '^') {
+			return false
+		}
+	}
+	return true
+}
+
+// reportUnmatched records a diagnostic in strict mode for a caret at offset that has no
+// valid closing delimiter. Carets at the start of a line or after whitespace could never open a
+// superscript, so they are not reported.
+func (s *superscriptParser) reportUnmatched(block text.Reader, pc parser.Context, offset int) {
+	if !s.cfg.strict {
+		return
+	}
+	if before := block.PrecendingCharacter(); unicode.IsSpace(before) || before == -1 {
+		return
+	}
+	addDiagnostic(pc, offset, "unmatched opening caret")
+}
+
+// insideMath reports whether the reader is positioned between unescaped '$' math
+// delimiters on the current source line. A run of dollar signs ($$) counts as a single
+// delimiter, and dollar signs inside backtick code spans are skipped. Math spans that
+// wrap onto another line are not detected.
+//
+// When a math extension that parses $...$ is registered, its parser consumes the whole
+// span before this parser sees the caret; this check covers documents rendered without
+// one, or where the math parser runs after this one.
+func insideMath(block text.Reader) bool {
+	source := block.Source()
+	_, pos := block.Position()
+	before := source[bytes.LastIndexByte(source[:pos.Start], '\n')+1 : pos.Start]
+	open := false
+	for i := 0; i < len(before); i++ {
+		if before[i] == '`' {
+			i = skipCodeSpan(before, i)
+			continue
+		}
+		if before[i] != '$' || (i > 0 && before[i-1] == '\\') {
+			continue
+		}
+		open = !open
+		for i+1 < len(before) && before[i+1] == '$' {
+			i++
+		}
+	}
+	if !open {
+		return false
+	}
+	line, _ := block.PeekLine()
+	for i := 1; i < len(line); i++ {
+		if line[i] == '`' {
+			i = skipCodeSpan(line, i)
+			continue
+		}
+		if line[i] == '$' && line[i-1] != '\\' {
+			return true
+		}
+	}
+	return false
+}
+
 // CloseBlock implements parser.InlineParser.CloseBlock.
 func (s *superscriptParser) CloseBlock(parent ast.Node, pc parser.Context) {
 	// nothing to do
 }
 
 // SuperscriptHTMLRenderer renders superscript nodes as HTML <sup> elements.
+//
+// It embeds html.Config, so renderer-wide settings such as html.WithXHTML or
+// html.WithUnsafe passed to goldmark.WithRendererOptions apply to it as they do to the
+// core renderer.
+//
+// Superscript content is plain text, so it is always written through the configured
+// html.Writer, which escapes markup and resolves entities in both safe and unsafe modes.
+// Options that rewrite the content must preserve this.
 type SuperscriptHTMLRenderer struct {
 	html.Config
+	cfg Config
 }
 
 // NewSuperscriptHTMLRenderer returns a new SuperscriptHTMLRenderer with the given options.
 func NewSuperscriptHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	return NewSuperscriptHTMLRendererWithConfig(&Config{}, opts...)
+}
+
+// NewSuperscriptHTMLRendererWithConfig returns a new SuperscriptHTMLRenderer that uses
+// the renderer settings in cfg and the given HTML options.
+func NewSuperscriptHTMLRendererWithConfig(cfg *Config, opts ...html.Option) renderer.NodeRenderer {
 	r := &SuperscriptHTMLRenderer{
 		Config: html.NewConfig(),
+		cfg:    *cfg,
 	}
 	for _, opt := range opts {
 		opt.SetHTMLOption(&r.Config)
@@ -173,52 +1110,755 @@ func NewSuperscriptHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 // RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
 func (r *SuperscriptHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(KindSuperscript, r.renderSuperscript)
+	reg.Register(KindIsotope, r.renderIsotope)
 }
 
+// pdfStyle is the inline style written by WithPDFStyle.
+const pdfStyle = "vertical-align:super;font-size:smaller"
+
 // SuperscriptAttributeFilter defines attribute names which superscript elements can have.
 // Uses the global HTML attribute filter for consistency with other HTML elements.
 var SuperscriptAttributeFilter = html.GlobalAttributeFilter
 
 func (r *SuperscriptHTMLRenderer) renderSuperscript(
 	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
-	if entering {
-		if n.Attributes() != nil {
-			_, _ = w.WriteString("<sup")
-			html.RenderAttributes(w, n, SuperscriptAttributeFilter)
-			_ = w.WriteByte('>')
+	if r.cfg.flatten {
+		if entering {
+			r.Writer.Write(w, nodeContent(n, source))
+		}
+		return ast.WalkSkipChildren, nil
+	}
+	if entering && r.cfg.adjacentSeparator != "" {
+		if _, ok := n.PreviousSibling().(*Node); ok {
+			_, _ = w.WriteString(r.cfg.adjacentSeparator)
+		}
+	}
+	if r.cfg.debugComments {
+		// Exit is called for every node, whichever way it was rendered
+		if entering {
+			_, _ = w.WriteString("<!-- sup:start -->")
 		} else {
-			_, _ = w.WriteString("<sup>")
+			defer func() { _, _ = w.WriteString("<!-- sup:end -->") }()
+		}
+	}
+	if r.cfg.renderHook != nil {
+		handled, err := r.cfg.renderHook(w, source, n, entering)
+		if err != nil {
+			return ast.WalkStop, err
+		}
+		if handled {
+			if entering {
+				return ast.WalkSkipChildren, nil
+			}
+			return ast.WalkContinue, nil
+		}
+	}
+	if indicator := r.ordinalIndicator(n, source); indicator != "" {
+		if entering {
+			_, _ = w.WriteString(indicator)
+		}
+		return ast.WalkSkipChildren, nil
+	}
+	if symbol, ok := r.cfg.symbols[string(nodeContent(n, source))]; ok {
+		if entering {
+			_, _ = w.WriteString(symbol)
+		}
+		return ast.WalkSkipChildren, nil
+	}
+	if r.cfg.unicodeOutput {
+		if content := unicodeContent(nodeContent(n, source)); content != nil {
+			if entering {
+				_, _ = w.Write(util.EscapeHTML(content))
+			}
+			return ast.WalkSkipChildren, nil
+		}
+	}
+	if r.cfg.commaSplit {
+		if parts := splitParts(nodeContent(n, source)); parts != nil {
+			if entering {
+				r.renderParts(w, source, n, parts)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+	}
+	content := nodeContent(n, source)
+	opening, closing := r.delimiters(n, source)
+	if entering {
+		r.renderOpen(w, content, r.attributes(source, n), opening)
+		if rendered, replaced := r.renderedContent(content); replaced {
+			r.renderContent(w, rendered)
+			return ast.WalkSkipChildren, nil
 		}
 	} else {
-		_, _ = w.WriteString("</sup>")
+		r.renderClose(w, content, closing)
 	}
 	return ast.WalkContinue, nil
 }
 
-// superscript implements goldmark.Extender for the superscript extension.
-type superscript struct{}
+// renderOpen writes what comes before the content of an element with the given content
+// and attributes: the WithWrap prefix, the opening tag, the opening delimiter when
+// delimiters are shown, and the opening link tag.
+func (r *SuperscriptHTMLRenderer) renderOpen(w util.BufWriter, content []byte, attrs []ast.Attribute, opening []byte) {
+	_, _ = w.WriteString(r.cfg.wrapPrefix)
+	r.renderOpening(w, content, attrs)
+	r.renderDelimiter(w, opening)
+	if href, rel, linked := r.link(content); linked {
+		_, _ = w.WriteString(`<a href="`)
+		if r.Unsafe || !html.IsDangerousURL([]byte(href)) {
+			writeAttributeValue(w, util.URLEscape([]byte(href), true))
+		}
+		_ = w.WriteByte('"')
+		if rel != "" {
+			_, _ = w.WriteString(` rel="`)
+			writeAttributeValue(w, []byte(rel))
+			_ = w.WriteByte('"')
+		}
+		_ = w.WriteByte('>')
+	}
+}
+
+// renderClose writes what comes after the content of an element with the given content,
+// in the reverse order of renderOpen, followed by the screen reader text and the break
+// hint.
+func (r *SuperscriptHTMLRenderer) renderClose(w util.BufWriter, content, closing []byte) {
+	if _, _, linked := r.link(content); linked {
+		_, _ = w.WriteString("</a>")
+	}
+	r.renderDelimiter(w, closing)
+	r.renderSROnly(w, content)
+	r.renderClosing(w, content)
+	_, _ = w.WriteString(r.cfg.wrapSuffix)
+	r.renderBreakHint(w, content)
+}
+
+// renderedContent returns content as changed by the options that rewrite it: sign
+// normalization, numeral mapping and the render transform. replaced reports whether
+// the renderer writes the content itself rather than leaving it to the text nodes,
+// which is also the case with the content entity options.
+func (r *SuperscriptHTMLRenderer) renderedContent(content []byte) (rendered []byte, replaced bool) {
+	content, replaced = r.signedContent(content)
+	if r.cfg.numerals != nil {
+		content, replaced = mapNumerals(content, r.cfg.numerals), true
+	}
+	if r.cfg.renderTransform != nil {
+		// The content may alias the source, which the hook must not be able to change
+		content, replaced = r.cfg.renderTransform(append([]byte(nil), content...)), true
+	}
+	if r.cfg.contentEntities != EntitiesNone || r.cfg.escapeContentEntities {
+		replaced = true
+	}
+	return content, replaced
+}
+
+// renderContent writes content returned by renderedContent.
+func (r *SuperscriptHTMLRenderer) renderContent(w util.BufWriter, content []byte) {
+	switch {
+	case r.cfg.contentEntities != EntitiesNone:
+		writeEntities(w, content, r.cfg.contentEntities)
+	case r.cfg.escapeContentEntities:
+		_, _ = w.Write(util.EscapeHTML(util.UnescapePunctuations(content)))
+	default:
+		r.Writer.Write(w, content)
+	}
+}
+
+// splitParts splits content at its commas for WithCommaSplit. It returns nil when there
+// is no comma or when any part would be empty, as in 2,,3 or 2, so that the
+// superscript is rendered as a single element.
+func splitParts(content []byte) [][]byte {
+	parts := bytes.Split(content, []byte{','})
+	if len(parts) < 2 {
+		return nil
+	}
+	for _, part := range parts {
+		if len(part) == 0 {
+			return nil
+		}
+	}
+	return parts
+}
+
+// renderParts writes a separate element for each of the comma-separated parts of the
+// content of n, for WithCommaSplit, joined by the configured separator. Each element is
+// rendered like a superscript of its own with the part as content and the attributes
+// of n, except that an id is only written on the first. The delimiters, when shown,
+// open the first element and close the last.
+func (r *SuperscriptHTMLRenderer) renderParts(w util.BufWriter, source []byte, n ast.Node, parts [][]byte) {
+	attrs := r.attributes(source, n)
+	opening, closing := r.delimiters(n, source)
+	for i, part := range parts {
+		if i > 0 {
+			_, _ = w.WriteString(r.cfg.commaSeparator)
+			if j := attributeIndex(attrs, []byte("id")); j != -1 {
+				attrs = append(attrs[:j:j], attrs[j+1:]...)
+			}
+		}
+		var partOpening, partClosing []byte
+		if i == 0 {
+			partOpening = opening
+		}
+		if i == len(parts)-1 {
+			partClosing = closing
+		}
+		r.renderOpen(w, part, attrs, partOpening)
+		rendered, _ := r.renderedContent(part)
+		r.renderContent(w, rendered)
+		r.renderClose(w, part, partClosing)
+	}
+}
+
+// renderOpening writes the opening tag of an element with the given content and
+// attributes, or the opening marker set by WithDelimiterFunc.
+func (r *SuperscriptHTMLRenderer) renderOpening(w util.BufWriter, content []byte, attrs []ast.Attribute) {
+	if r.cfg.delimiterFunc {
+		if r.cfg.openFunc != nil {
+			_, _ = w.WriteString(r.cfg.openFunc(content))
+		}
+		return
+	}
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(r.tag())
+	writeAttributes(w, attrs)
+	_ = w.WriteByte('>')
+}
+
+// renderClosing writes the closing tag of an element with the given content, or the
+// closing marker set by WithDelimiterFunc.
+func (r *SuperscriptHTMLRenderer) renderClosing(w util.BufWriter, content []byte) {
+	if r.cfg.delimiterFunc {
+		if r.cfg.closeFunc != nil {
+			_, _ = w.WriteString(r.cfg.closeFunc(content))
+		}
+		return
+	}
+	_, _ = w.WriteString("</")
+	_, _ = w.WriteString(r.tag())
+	_ = w.WriteByte('>')
+}
+
+// mapNumerals returns a copy of content with the ASCII digits replaced as given by
+// numerals. Other runes, and digits without a replacement, are kept.
+func mapNumerals(content []byte, numerals map[rune]rune) []byte {
+	return bytes.Map(func(c rune) rune {
+		if m, ok := numerals[c]; ok && c >= '0' && c <= '9' {
+			return m
+		}
+		return c
+	}, content)
+}
+
+// signedContent returns content with a leading hyphen replaced by a minus sign when
+// WithSignNormalization is set, and reports whether that happened.
+func (r *SuperscriptHTMLRenderer) signedContent(content []byte) ([]byte, bool) {
+	if !r.cfg.signNormalization || len(content) == 0 || content[0] != '-' {
+		return content, false
+	}
+	return append([]byte("\u2212"), content[1:]...), true
+}
+
+// delimiters returns the opening and closing delimiters of n as written in the source
+// when WithShowDelimiters is set. Either is nil when n has none: an implicitly closed
+// superscript has no closing delimiter, and the levels of a tower share the closing
+// delimiter of the innermost one.
+func (r *SuperscriptHTMLRenderer) delimiters(n ast.Node, source []byte) ([]byte, []byte) {
+	sup, ok := n.(*Node)
+	if !r.cfg.showDelimiters || !ok || sup.span.Len() < 2 {
+		return nil, nil
+	}
+	markup := sup.span.Value(source)
+	if r.cfg.doubleCaret && len(markup) > 2*len(doubleCaret) &&
+		bytes.HasPrefix(markup, doubleCaret) && bytes.HasSuffix(markup, doubleCaret) {
+		return doubleCaret, doubleCaret
+	}
+	first, size := utf8.DecodeRune(markup)
+	opening := markup[:size]
+	closer, closerLen := r.cfg.closer()
+	if first != '^' {
+		closer, closerLen = first, size
+	}
+	closing := markup[len(markup)-closerLen:]
+	if last, _ := utf8.DecodeRune(closing); last != closer || len(markup) <= closerLen {
+		closing = nil
+	}
+	if inner, ok := sup.LastChild().(*Node); ok && inner.span.Stop == sup.span.Stop {
+		closing = nil
+	}
+	return opening, closing
+}
+
+// renderDelimiter writes a delimiter returned by delimiters, wrapped in a span with the
+// class set by WithDelimiterClass.
+func (r *SuperscriptHTMLRenderer) renderDelimiter(w util.BufWriter, delimiter []byte) {
+	if delimiter == nil {
+		return
+	}
+	if r.cfg.delimiterClass == "" {
+		_, _ = w.Write(util.EscapeHTML(delimiter))
+		return
+	}
+	_, _ = w.WriteString(`<span class="`)
+	writeAttributeValue(w, []byte(r.cfg.delimiterClass))
+	_, _ = w.WriteString(`">`)
+	_, _ = w.Write(util.EscapeHTML(delimiter))
+	_, _ = w.WriteString("</span>")
+}
+
+// renderSROnly writes the visually hidden expansion of content configured by WithSROnly.
+func (r *SuperscriptHTMLRenderer) renderSROnly(w util.BufWriter, content []byte) {
+	if r.cfg.srOnlyFunc == nil {
+		return
+	}
+	expansion := r.cfg.srOnlyFunc(content)
+	if expansion == "" {
+		return
+	}
+	_, _ = w.WriteString(`<span class="`)
+	writeAttributeValue(w, []byte(r.cfg.srOnlyClass))
+	_, _ = w.WriteString(`">`)
+	_, _ = w.Write(util.EscapeHTML([]byte(expansion)))
+	_, _ = w.WriteString("</span>")
+}
+
+// renderBreakHint writes a <wbr> after an element whose content is longer than the
+// threshold set by WithBreakHint.
+func (r *SuperscriptHTMLRenderer) renderBreakHint(w util.BufWriter, content []byte) {
+	if r.cfg.breakHint <= 0 || utf8.RuneCount(content) <= r.cfg.breakHint {
+		return
+	}
+	if r.XHTML {
+		_, _ = w.WriteString("<wbr />")
+		return
+	}
+	_, _ = w.WriteString("<wbr>")
+}
+
+// ordinalIndicator returns the ordinal indicator that n is rendered as with
+// WithRomanceOrdinals, or "" if there is none: º for a superscript o and ª for a
+// superscript a, right after a digit.
+func (r *SuperscriptHTMLRenderer) ordinalIndicator(n ast.Node, source []byte) string {
+	sup, ok := n.(*Node)
+	if !r.cfg.romanceOrdinals || !ok || sup.span.Start == 0 {
+		return ""
+	}
+	if before := source[sup.span.Start-1]; before < '0' || before > '9' {
+		return ""
+	}
+	switch string(nodeContent(n, source)) {
+	case "o":
+		return "\u00ba"
+	case "a":
+		return "\u00aa"
+	}
+	return ""
+}
+
+// tag returns the name of the element superscripts are rendered as.
+func (r *SuperscriptHTMLRenderer) tag() string {
+	if r.cfg.spanMode {
+		return "span"
+	}
+	return "sup"
+}
+
+// link returns the link target for content when WithCitation or WithLinkFunc links it,
+// and the rel of the link, which is "cite" for citations.
+func (r *SuperscriptHTMLRenderer) link(content []byte) (string, string, bool) {
+	if r.cfg.citationFunc != nil {
+		if id, ok := r.cfg.citationFunc(content); ok {
+			return "#cite-" + id, "cite", true
+		}
+	}
+	if r.cfg.linkFunc == nil {
+		return "", "", false
+	}
+	href, ok := r.cfg.linkFunc(content)
+	return href, "", ok
+}
+
+// attributes returns the attributes of n merged with the attributes generated by the
+// renderer's options. Generated classes are added to any class already on the node;
+// for other attributes the node's value takes precedence. The result is sorted by name
+// when deterministic attribute output is enabled.
+func (r *SuperscriptHTMLRenderer) attributes(source []byte, n ast.Node) []ast.Attribute {
+	generated := r.generatedAttributes(source, n)
+	if len(generated) == 0 && !r.cfg.deterministicAttributes {
+		return n.Attributes()
+	}
+	attrs := append([]ast.Attribute(nil), n.Attributes()...)
+	for _, attr := range generated {
+		i := attributeIndex(attrs, attr.Name)
+		switch {
+		case i == -1:
+			attrs = append(attrs, attr)
+		case bytes.Equal(attr.Name, classAttribute):
+			attrs[i].Value = joinClasses(attrs[i].Value, attr.Value)
+		}
+	}
+	if r.cfg.deterministicAttributes {
+		sort.SliceStable(attrs, func(i, j int) bool {
+			return bytes.Compare(attrs[i].Name, attrs[j].Name) < 0
+		})
+	}
+	merged := NewSuperscriptNode()
+	for _, attr := range attrs {
+		merged.SetAttribute(attr.Name, attr.Value)
+	}
+	return merged.Attributes()
+}
+
+// writeAttributes writes the attributes allowed by SuperscriptAttributeFilter, and any
+// data-* attribute, like html.RenderAttributes.
+func writeAttributes(w util.BufWriter, attrs []ast.Attribute) {
+	for _, attr := range attrs {
+		if SuperscriptAttributeFilter != nil && !SuperscriptAttributeFilter.Contains(attr.Name) &&
+			!bytes.HasPrefix(attr.Name, []byte("data-")) {
+			continue
+		}
+		writeAttribute(w, attr)
+	}
+}
 
-// SuperscriptOption configures the superscript extension.
-type SuperscriptOption func(*superscript)
+// writeAttribute writes attr as a name="value" pair preceded by a space.
+func writeAttribute(w util.BufWriter, attr ast.Attribute) {
+	_ = w.WriteByte(' ')
+	_, _ = w.Write(attr.Name)
+	_, _ = w.WriteString(`="`)
+	switch value := attr.Value.(type) {
+	case []byte:
+		writeAttributeValue(w, value)
+	case string:
+		writeAttributeValue(w, []byte(value))
+	}
+	_ = w.WriteByte('"')
+}
+
+// writeAttributeValue writes value escaped for use inside a double-quoted attribute, so
+// that no content can end the attribute or start markup. Every attribute written by the
+// HTML renderer goes through it.
+func writeAttributeValue(w util.BufWriter, value []byte) {
+	_, _ = w.Write(util.EscapeHTML(value))
+}
+
+var classAttribute = []byte("class")
+
+// attributeIndex returns the index of the attribute called name in attrs, or -1.
+func attributeIndex(attrs []ast.Attribute, name []byte) int {
+	for i, attr := range attrs {
+		if bytes.Equal(attr.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// joinClasses appends class to the space-separated class list in value, which may be
+// a string or a byte slice.
+func joinClasses(value, class any) []byte {
+	var joined []byte
+	for _, v := range []any{value, class} {
+		var b []byte
+		switch typed := v.(type) {
+		case []byte:
+			b = typed
+		case string:
+			b = []byte(typed)
+		}
+		if len(b) == 0 {
+			continue
+		}
+		if len(joined) > 0 {
+			joined = append(joined, ' ')
+		}
+		joined = append(joined, b...)
+	}
+	return joined
+}
+
+// generatedAttributes returns the attributes added to every superscript by the
+// renderer's options.
+func (r *SuperscriptHTMLRenderer) generatedAttributes(source []byte, n ast.Node) []ast.Attribute {
+	var attrs []ast.Attribute
+	if r.cfg.spanMode {
+		attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(r.cfg.spanClass)})
+	}
+	if r.cfg.footnoteSafeClass != "" {
+		attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(r.cfg.footnoteSafeClass)})
+	}
+	if r.cfg.bemBlock != "" {
+		attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(r.cfg.bemBlock + "__sup")})
+	}
+	if r.cfg.classFunc != nil {
+		if class := r.cfg.classFunc(nodeContent(n, source)); class != "" {
+			if r.cfg.bemBlock != "" {
+				class = r.cfg.bemBlock + "__sup--" + class
+			}
+			attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(class)})
+		}
+	} else if r.cfg.footnoteSafeClass == "" && r.cfg.bemBlock == "" {
+		if class := r.metaDefault(n, r.cfg.metaKeys.Class); class != "" {
+			attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(class)})
+		}
+	}
+	if r.cfg.contextClass {
+		if class := contextClass(n); class != "" {
+			attrs = append(attrs, ast.Attribute{Name: classAttribute, Value: []byte(class)})
+		}
+	}
+	if r.cfg.autoID {
+		if sup, ok := n.(*Node); ok && sup.seq > 0 {
+			attrs = append(attrs, ast.Attribute{Name: []byte("id"), Value: []byte(r.cfg.autoIDPrefix + strconv.Itoa(sup.seq))})
+		}
+	}
+	if r.cfg.sourceAttribute {
+		if sup, ok := n.(*Node); ok && sup.span.Len() > 0 {
+			attrs = append(attrs, ast.Attribute{Name: []byte("data-md"), Value: sup.span.Value(source)})
+		}
+	}
+	if r.cfg.positionAttributes {
+		if start, stop, ok := contentPosition(n); ok {
+			attrs = append(attrs,
+				ast.Attribute{Name: []byte("data-start"), Value: []byte(strconv.Itoa(start))},
+				ast.Attribute{Name: []byte("data-stop"), Value: []byte(strconv.Itoa(stop))})
+		}
+	}
+	if r.cfg.unicodeOutput && r.cfg.unicodePartialMarker {
+		attrs = append(attrs, ast.Attribute{Name: []byte("data-unicode"), Value: []byte("partial")})
+	}
+	if lang := r.cfg.lang; lang != "" {
+		attrs = append(attrs, ast.Attribute{Name: []byte("lang"), Value: []byte(lang)})
+	} else if lang := r.metaDefault(n, r.cfg.metaKeys.Lang); lang != "" {
+		attrs = append(attrs, ast.Attribute{Name: []byte("lang"), Value: []byte(lang)})
+	}
+	if r.cfg.itemprop != "" {
+		attrs = append(attrs, ast.Attribute{Name: []byte("itemprop"), Value: []byte(r.cfg.itemprop)})
+	}
+	if r.cfg.titleFunc != nil {
+		if title := r.cfg.titleFunc(nodeContent(n, source)); title != "" {
+			attrs = append(attrs, ast.Attribute{Name: []byte("title"), Value: []byte(title)})
+		}
+	}
+	if r.cfg.pdfStyle {
+		attrs = append(attrs, ast.Attribute{Name: []byte("style"), Value: []byte(pdfStyle)})
+	}
+	if r.cfg.occurrenceFunc != nil {
+		if sup, ok := n.(*Node); ok && sup.seq > 0 {
+			attrs = append(attrs, r.cfg.occurrenceFunc(sup.seq-1, nodeContent(n, source))...)
+		}
+	}
+	return attrs
+}
+
+// contextClass returns the classes set by WithContextClass for the emphasis n is
+// inside, from the innermost out: in-em for emphasis and in-strong for strong emphasis.
+// Only ancestors within the enclosing block count.
+func contextClass(n ast.Node) string {
+	var classes []string
+	seen := map[string]bool{}
+	for p := n.Parent(); p != nil && p.Type() != ast.TypeBlock; p = p.Parent() {
+		em, ok := p.(*ast.Emphasis)
+		if !ok {
+			continue
+		}
+		class := "in-em"
+		if em.Level == 2 {
+			class = "in-strong"
+		}
+		if !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+// contentPosition returns the byte offsets in the source of the start and end of the
+// content of n, from its first to its last text segment. It reports false when the
+// content has no text segments, as for a superscript built outside the parser or with
+// content replaced by WithNormalize or WithTrimZeroWidth.
+func contentPosition(n ast.Node) (int, int, bool) {
+	start, stop := -1, -1
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			if start == -1 {
+				start = t.Segment.Start
+			}
+			stop = t.Segment.Stop
+		}
+		return ast.WalkContinue, nil
+	})
+	return start, stop, start != -1
+}
+
+// metaDefault returns the string stored under key in the metadata of the document
+// that n belongs to, or "" if key is empty or holds no string.
+func (r *SuperscriptHTMLRenderer) metaDefault(n ast.Node, key string) string {
+	if key == "" {
+		return ""
+	}
+	doc := n.OwnerDocument()
+	if doc == nil {
+		return ""
+	}
+	value, _ := doc.Meta()[key].(string)
+	return value
+}
+
+// nodeContent returns the content of the text and string descendants of a superscript node.
+func nodeContent(n ast.Node, source []byte) []byte {
+	return appendContent(nil, n, source)
+}
+
+// appendContent appends the text of the descendants of n to content, so that content
+// parsed by WithInlineContent is included without its markup.
+func appendContent(content []byte, n ast.Node, source []byte) []byte {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			content = append(content, t.Segment.Value(source)...)
+		case *ast.String:
+			content = append(content, t.Value...)
+		default:
+			content = appendContent(content, c, source)
+		}
+	}
+	return content
+}
+
+// namedEntities maps runes to the named character references used by EntitiesNamed.
+// The table is deliberately small and limited to names supported by HTML 4.
+var namedEntities = map[rune]string{
+	'"':      "&quot;",
+	'&':      "&amp;",
+	'<':      "&lt;",
+	'>':      "&gt;",
+	'\u00a9': "&copy;",
+	'\u00aa': "&ordf;",
+	'\u00ae': "&reg;",
+	'\u00b0': "&deg;",
+	'\u00b1': "&plusmn;",
+	'\u00b2': "&sup2;",
+	'\u00b3': "&sup3;",
+	'\u00b7': "&middot;",
+	'\u00b9': "&sup1;",
+	'\u00ba': "&ordm;",
+	'\u00d7': "&times;",
+	'\u00f7': "&divide;",
+	'\u2032': "&prime;",
+	'\u2033': "&Prime;",
+	'\u2122': "&trade;",
+	'\u2212': "&minus;",
+	'\u221e': "&infin;",
+}
+
+// writeEntities writes content with every character encoded as a character reference.
+// Escapes and references already present in the content are resolved first so that
+// each character is encoded exactly once.
+func writeEntities(w util.BufWriter, content []byte, mode EntityMode) {
+	content = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(content)))
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		content = content[size:]
+		if mode == EntitiesNamed {
+			if name, ok := namedEntities[r]; ok {
+				_, _ = w.WriteString(name)
+				continue
+			}
+		}
+		_, _ = fmt.Fprintf(w, "&#x%x;", r)
+	}
+}
+
+// Priorities used by Extend to register the superscript parser and renderer, for
+// ordering other extensions relative to this one.
+const (
+	// DefaultPriority is the priority of every component registered by Extend.
+	DefaultPriority = 100
+	// DefaultParserPriority is the priority of the inline parser and AST transformers.
+	DefaultParserPriority = DefaultPriority
+	// DefaultRendererPriority is the priority of the HTML renderer.
+	DefaultRendererPriority = DefaultPriority
+	// SubscriptParserPriority is the fixed priority gm-subscript registers its inline
+	// parser at, for use with WithPairedPriority.
+	SubscriptParserPriority = 100
+)
+
+// superscript implements goldmark.Extender for the superscript extension.
+type superscript struct {
+	Config
+}
 
 // Superscript is a pre-configured superscript extension instance.
 var Superscript = NewSuperscript()
 
-// NewSuperscript creates a new superscript extension with the given options.
+// NewSuperscript creates a new superscript extension with the given options. Settings
+// that NewSuperscriptE would report as invalid fall back to their defaults, such as
+// the caret for a whitespace close delimiter; use NewSuperscriptE to detect them.
 func NewSuperscript(opts ...SuperscriptOption) *superscript {
-	s := &superscript{}
-	for _, opt := range opts {
-		opt(s)
+	s, err := NewSuperscriptE(opts...)
+	if err != nil {
+		s.resetInvalid()
 	}
 	return s
 }
 
+// NewSuperscriptE creates a new superscript extension with the given options and
+// returns an error if any of them was given an invalid value. The extension is
+// returned even when an error is reported.
+func NewSuperscriptE(opts ...SuperscriptOption) (*superscript, error) {
+	s := &superscript{}
+	s.apply(opts)
+	return s, s.Validate()
+}
+
+// Configure applies opts to the extension on top of the options it was created with,
+// for frameworks that construct an extension first and configure it later. It must be
+// called before Extend: the parser and renderer copy the settings when they are
+// registered, so later calls do not affect a goldmark.Markdown already built with the
+// extension. Configure does not validate the result; call Validate for that. The shared
+// Superscript instance must not be configured.
+func (s *superscript) Configure(opts ...SuperscriptOption) {
+	s.apply(opts)
+}
+
 // Extend implements goldmark.Extender by adding superscript parsing and rendering to the markdown processor.
 func (s *superscript) Extend(m goldmark.Markdown) {
+	parserPriority, rendererPriority := s.priorities()
 	m.Parser().AddOptions(parser.WithInlineParsers(
-		util.Prioritized(NewSuperscriptParser(), 100),
+		util.Prioritized(NewSuperscriptParserWithConfig(&s.Config), parserPriority),
 	))
+	if s.inlineContent {
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewInlineContentTransformer(m.Parser()), parserPriority),
+		))
+	}
+	if s.autoID || s.occurrenceFunc != nil {
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewAutoIDTransformer(), parserPriority),
+		))
+	}
+	if hasWideDelimiter(s.delimiters) {
+		// goldmark runs lower priorities first, so this runs before the other
+		// transformers and they see these superscripts too.
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewDelimiterTransformer(&s.Config), parserPriority-1),
+		))
+	}
+	if s.encodeStrayCarets {
+		// Runs after the other transformers, once every superscript is in place.
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewStrayCaretTransformer(), parserPriority+1),
+		))
+	}
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
-		util.Prioritized(NewSuperscriptHTMLRenderer(), 100),
+		util.Prioritized(NewSuperscriptHTMLRendererWithConfig(&s.Config), rendererPriority),
 	))
-}
\ No newline at end of file
+}
+
+// priorities returns the priorities Extend registers the parser, with its transformers,
+// and the renderer at.
+func (s *superscript) priorities() (int, int) {
+	if !s.pairedPriority {
+		return DefaultParserPriority, DefaultRendererPriority
+	}
+	return s.priorityBase, s.priorityBase
+}
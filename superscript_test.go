@@ -1,12 +1,25 @@
 package superscript
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 	subscript "github.com/zmtcreative/gm-subscript"
+	"golang.org/x/text/unicode/norm"
 )
 
 type TestCase struct {
@@ -15,6 +28,20 @@ type TestCase struct {
 	html string
 }
 
+// runTestCases renders each test case with md and compares it to the expected HTML.
+func runTestCases(t *testing.T, md goldmark.Markdown, testCases []TestCase) {
+	t.Helper()
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			testutil.DoTestCase(md, testutil.MarkdownTestCase{
+				Description: tc.desc,
+				Markdown:    tc.md,
+				Expected:    tc.html,
+			}, t)
+		})
+	}
+}
+
 func TestGoldmarkOnly(t *testing.T) {
 	// These tests are to show how Goldmark handles carats by default,
 	// without our extension enabled.
@@ -231,4 +258,3255 @@ func TestSuperscriptAdvanced(t *testing.T) {
 		})
 	}
 
-}
\ No newline at end of file
+}
+
+func TestSuperscriptContentEntities(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithContentEntities(EntitiesNamed)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Content entities: named references where available",
+			md:   `a^2&times;n^, b^&deg;^, c^&#x2122;^`,
+			html: `<p>a<sup>&#x32;&times;&#x6e;</sup>, b<sup>&deg;</sup>, c<sup>&trade;</sup></p>`,
+		},
+		{
+			desc: "Content entities: raw characters with named entities",
+			md:   `x^±1^ y^<b>^`,
+			html: `<p>x<sup>&plusmn;&#x31;</sup> y<sup>&lt;&#x62;&gt;</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithContentEntities(EntitiesNumeric)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Content entities: numeric references only",
+			md:   `a^2&times;n^`,
+			html: `<p>a<sup>&#x32;&#xd7;&#x6e;</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSkipInMath(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSkipInMath()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Skip in math: caret inside math span is untouched",
+			md:   `$a^2$ and $x^2^$`,
+			html: `<p>$a^2$ and $x^2^$</p>`,
+		},
+		{
+			desc: "Skip in math: superscript outside math still works",
+			md:   `a^2^ and $b^2^$ then c^2^`,
+			html: `<p>a<sup>2</sup> and $b^2^$ then c<sup>2</sup></p>`,
+		},
+		{
+			desc: "Skip in math: display math delimiters",
+			md:   `$$e^i\pi^$$ but e^x^`,
+			html: `<p>$$e^i\pi^$$ but e<sup>x</sup></p>`,
+		},
+		{
+			desc: "Skip in math: escaped dollar signs are not delimiters",
+			md:   `\$5 a^2^ \$6`,
+			html: `<p>$5 a<sup>2</sup> $6</p>`,
+		},
+		{
+			desc: "Skip in math: unclosed dollar sign",
+			md:   `costs $5, x^2^`,
+			html: `<p>costs $5, x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Skip in math: dollar signs in code spans are not delimiters",
+			md:   "`$` x^2^ costs $5 and y^2^ `$`",
+			html: `<p><code>$</code> x<sup>2</sup> costs $5 and y<sup>2</sup> <code>$</code></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Skip in math: disabled by default",
+			md:   `$x^2^$`,
+			html: `<p>$x<sup>2</sup>$</p>`,
+		},
+	})
+}
+
+// benchmarkInputs are realistic documents used by the parser benchmarks.
+var benchmarkInputs = []struct {
+	name string
+	md   string
+}{
+	{
+		name: "CaretHeavy",
+		md:   strings.Repeat("The area is a^2^ + b^2^ = c^2^ and E=mc^2^ for the 1^st^ and 2^nd^ cases.\n", 200),
+	},
+	{
+		name: "CaretFree",
+		md:   strings.Repeat("Plain prose without any superscripts at all, just ordinary words and punctuation.\n", 200),
+	},
+	{
+		name: "Unclosed",
+		md:   strings.Repeat("Exponents like x^2 and y^3 are written without closing carets on this long line of text.\n", 200),
+	},
+	{
+		name: "LongParagraph",
+		md:   strings.Repeat("x^2^ ", 20000),
+	},
+}
+
+func BenchmarkParse(b *testing.B) {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+	for _, input := range benchmarkInputs {
+		source := []byte(input.md)
+		b.Run(input.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(source)))
+			for i := 0; i < b.N; i++ {
+				md.Parser().Parse(text.NewReader(source))
+			}
+		})
+	}
+}
+
+func TestSuperscriptCloseDelimiter(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCloseDelimiter('°')),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Close delimiter: degree sign closes the superscript",
+			md:   `x^2° + y^n+1°`,
+			html: `<p>x<sup>2</sup> + y<sup>n+1</sup></p>`,
+		},
+		{
+			desc: "Close delimiter: caret no longer closes",
+			md:   `x^2^ and y^2`,
+			html: `<p>x^2^ and y^2</p>`,
+		},
+		{
+			desc: "Close delimiter: empty content",
+			md:   `x^°`,
+			html: `<p>x^°</p>`,
+		},
+		{
+			desc: "Close delimiter: a later caret opens the superscript",
+			md:   `x^2^3°`,
+			html: `<p>x^2<sup>3</sup></p>`,
+		},
+	})
+}
+
+// attributeTransformer sets the given attributes, in order, on every superscript node.
+type attributeTransformer []ast.Attribute
+
+func (a attributeTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == KindSuperscript {
+			for _, attr := range a {
+				n.SetAttribute(attr.Name, attr.Value)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func TestSuperscriptDeterministicAttributes(t *testing.T) {
+	attrs := attributeTransformer{
+		{Name: []byte("role"), Value: []byte("note")},
+		{Name: []byte("data-unit"), Value: []byte("m")},
+		{Name: []byte("class"), Value: []byte("exp")},
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDeterministicAttributes()),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attrs, 100)),
+		),
+	)
+
+	testCases := []TestCase{
+		{
+			desc: "Deterministic attributes: sorted by name",
+			md:   `x^2^`,
+			html: `<p>x<sup class="exp" data-unit="m" role="note">2</sup></p>`,
+		},
+	}
+	// Render repeatedly to show the order does not vary between renders.
+	for i := 0; i < 3; i++ {
+		runTestCases(t, mdTest, testCases)
+	}
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attrs, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Deterministic attributes: insertion order by default",
+			md:   `x^2^`,
+			html: `<p>x<sup role="note" data-unit="m" class="exp">2</sup></p>`,
+		},
+	})
+}
+
+// BenchmarkParseSuperscript measures a single call to the inline parser on a valid
+// superscript, isolating its allocations from the rest of the goldmark pipeline.
+func BenchmarkParseSuperscript(b *testing.B) {
+	benchmarkParseSuperscript(b, NewSuperscriptParser(), false)
+}
+
+func TestSuperscriptRequireWordBoundaryBefore(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRequireWordBoundaryBefore()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Word boundary: letters and digits before the caret",
+			md:   `x^2^ and 10^6^ and é^2^`,
+			html: `<p>x<sup>2</sup> and 10<sup>6</sup> and é<sup>2</sup></p>`,
+		},
+		{
+			desc: "Word boundary: punctuation before the caret",
+			md:   `(a+b)^2^ and a,^2^ and !^x^`,
+			html: `<p>(a+b)^2^ and a,^2^ and !^x^</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Word boundary: punctuation allowed by default",
+			md:   `(a+b)^2^`,
+			html: `<p>(a+b)<sup>2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptTrimTrailingPunctuation(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTrimTrailingPunctuation()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Trim trailing punctuation: period",
+			md:   `x^2.^`,
+			html: `<p>x<sup>2</sup>.</p>`,
+		},
+		{
+			desc: "Trim trailing punctuation: comma",
+			md:   `x^2,^ y^3^`,
+			html: `<p>x<sup>2</sup>, y<sup>3</sup></p>`,
+		},
+		{
+			desc: "Trim trailing punctuation: multiple characters",
+			md:   `x^n+1.,^`,
+			html: `<p>x<sup>n+1</sup>.,</p>`,
+		},
+		{
+			desc: "Trim trailing punctuation: content entirely punctuation",
+			md:   `x^..^`,
+			html: `<p>x<sup>..</sup></p>`,
+		},
+		{
+			desc: "Trim trailing punctuation: interior punctuation and factorials are kept",
+			md:   `b^2,1^ and n^2!^`,
+			html: `<p>b<sup>2,1</sup> and n<sup>2!</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Trim trailing punctuation: kept inside by default",
+			md:   `x^2.^`,
+			html: `<p>x<sup>2.</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSourceAttribute(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSourceAttribute()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Source attribute: original delimiters and content",
+			md:   `x^n+1^`,
+			html: `<p>x<sup data-md="^n+1^">n+1</sup></p>`,
+		},
+		{
+			desc: "Source attribute: content is escaped",
+			md:   `a^2&times;"n"^`,
+			html: `<p>a<sup data-md="^2&amp;times;&quot;n&quot;^">2×&quot;n&quot;</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSourceAttribute(), WithCloseDelimiter('°')),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("class"), Value: []byte("exp")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Source attribute: alongside node attributes and an alternate delimiter",
+			md:   `x^2°`,
+			html: `<p>x<sup class="exp" data-md="^2°">2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptRespectFootnotes(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			extension.Footnote,
+			NewSuperscript(WithRespectFootnotes()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Respect footnotes: superscript inside square brackets is left literal",
+			md: `Hi, Albert![^1^]
+[^1]: E=mc^2^ is a famous equation.`,
+			html: `<p>Hi, Albert![^1^]</p>`,
+		},
+		{
+			desc: "Respect footnotes: footnote references still work",
+			md: `Hi, Albert![^1]
+[^1]: E=mc^2^ is a famous equation.`,
+			html: `<p>Hi, Albert!<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>E=mc<sup>2</sup> is a famous equation.&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		{
+			desc: "Respect footnotes: superscripts elsewhere in brackets",
+			md:   `[x^2^]`,
+			html: `<p>[x<sup>2</sup>]</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			extension.Footnote,
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Respect footnotes: disabled by default",
+			md: `Hi, Albert![^1^]
+[^1]: E=mc^2^ is a famous equation.`,
+			html: `<p>Hi, Albert![<sup>1</sup>]</p>`,
+		},
+	})
+}
+
+func TestSuperscriptMultiline(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMultiline("")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Multiline: content spanning two lines",
+			md:   "x^long\ncontent^ here",
+			html: `<p>x<sup>longcontent</sup> here</p>`,
+		},
+		{
+			desc: "Multiline: content spanning three lines",
+			md:   "x^a\nb\nc^ and y^2^",
+			html: `<p>x<sup>abc</sup> and y<sup>2</sup></p>`,
+		},
+		{
+			desc: "Multiline: continuation line with leading indentation",
+			md:   "x^long\n   content^",
+			html: `<p>x<sup>longcontent</sup></p>`,
+		},
+		{
+			desc: "Multiline: whitespace before the line break",
+			md:   "x^long content\nmore^",
+			html: "<p>x^long content\nmore^</p>",
+		},
+		{
+			desc: "Multiline: no closing caret in the paragraph",
+			md:   "x^long\ncontent",
+			html: "<p>x^long\ncontent</p>",
+		},
+		{
+			desc: "Multiline: hard line break ends the search",
+			md:   "x^long\\\ncontent^",
+			html: "<p>x^long<br>\ncontent^</p>",
+		},
+		{
+			desc: "Multiline: single-line superscripts are unchanged",
+			md:   "a^2^ + b^2^",
+			html: `<p>a<sup>2</sup> + b<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMultiline(" ")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Multiline: line break replaced by a space",
+			md:   "x^long\ncontent^",
+			html: `<p>x<sup>long content</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Multiline: disabled by default",
+			md:   "x^long\ncontent^",
+			html: "<p>x^long\ncontent^</p>",
+		},
+	})
+}
+
+func TestNewSuperscriptE(t *testing.T) {
+	testCases := []struct {
+		desc string
+		opts []SuperscriptOption
+		err  error
+	}{
+		{
+			desc: "NewSuperscriptE: no options",
+		},
+		{
+			desc: "NewSuperscriptE: valid options",
+			opts: []SuperscriptOption{WithCloseDelimiter('°'), WithContentEntities(EntitiesNamed)},
+		},
+		{
+			desc: "NewSuperscriptE: whitespace delimiter",
+			opts: []SuperscriptOption{WithCloseDelimiter(' ')},
+			err:  ErrInvalidDelimiter,
+		},
+		{
+			desc: "NewSuperscriptE: invalid rune delimiter",
+			opts: []SuperscriptOption{WithCloseDelimiter(0xD800)},
+			err:  ErrInvalidDelimiter,
+		},
+		{
+			desc: "NewSuperscriptE: unknown entity mode",
+			opts: []SuperscriptOption{WithContentEntities(EntityMode(42))},
+			err:  ErrInvalidEntityMode,
+		},
+		{
+			desc: "NewSuperscriptE: negative minimum content length",
+			opts: []SuperscriptOption{WithMinContentLength(-1)},
+			err:  ErrInvalidLength,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := NewSuperscriptE(tc.opts...)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("NewSuperscriptE() error = %v, want %v", err, tc.err)
+			}
+			if s == nil {
+				t.Error("NewSuperscriptE() returned a nil extension")
+			}
+		})
+	}
+}
+
+func TestNewSuperscriptInvalidOptions(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(
+				WithCloseDelimiter(' '),
+				WithDelimiters('^', '\n'),
+				WithContentEntities(EntityMode(42)),
+				WithMinContentLength(-1),
+				WithSourceAttribute(),
+			),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Invalid options: defaults are used instead",
+			md:   `x^2 y^2^`,
+			html: `<p>x^2 y<sup data-md="^2^">2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptNodePool(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithNodePool()),
+		),
+	)
+
+	// Release after each render so later cases parse into recycled nodes.
+	for i := 0; i < 3; i++ {
+		source := []byte(`a^2^ + b^n+1^ = c^2^`)
+		doc := mdTest.Parser().Parse(text.NewReader(source))
+		var buf bytes.Buffer
+		if err := mdTest.Renderer().Render(&buf, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		want := "<p>a<sup>2</sup> + b<sup>n+1</sup> = c<sup>2</sup></p>\n"
+		if buf.String() != want {
+			t.Errorf("render %d = %q, want %q", i, buf.String(), want)
+		}
+		Release(doc)
+	}
+}
+
+// benchmarkParseSuperscript parses a single valid superscript per iteration, optionally
+// releasing each node back to the pool.
+func benchmarkParseSuperscript(b *testing.B, p parser.InlineParser, release bool) {
+	pc := parser.NewContext()
+	source := []byte("x^n+1^ rest of line\n")
+	segments := text.NewSegments()
+	segments.Append(text.NewSegment(0, len(source)))
+	reader := text.NewBlockReader(source, segments)
+	// The caret follows the text before it in a paragraph, as during document parsing
+	parent := ast.NewParagraph()
+	parent.AppendChild(parent, ast.NewTextSegment(text.NewSegment(0, 1)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader.Reset(segments)
+		reader.Advance(1)
+		n := p.Parse(parent, reader, pc)
+		if n == nil {
+			b.Fatal("expected a superscript node")
+		}
+		if release {
+			Release(n)
+		}
+	}
+}
+
+func BenchmarkParseSuperscriptPooled(b *testing.B) {
+	benchmarkParseSuperscript(b, NewSuperscriptParserWithConfig(NewConfig(WithNodePool())), true)
+}
+
+func TestSuperscriptRenderHook(t *testing.T) {
+	trademark := func(w util.BufWriter, source []byte, n ast.Node, entering bool) (bool, error) {
+		if string(nodeContent(n, source)) != "TM" {
+			return false, nil
+		}
+		if entering {
+			_, _ = w.WriteString("™")
+		}
+		return true, nil
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRenderHook(trademark)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Render hook: hook handles TM content",
+			md:   `Acme^TM^`,
+			html: `<p>Acme™</p>`,
+		},
+		{
+			desc: "Render hook: other content falls through",
+			md:   `Acme^TM^ x^2^`,
+			html: `<p>Acme™ x<sup>2</sup></p>`,
+		},
+	})
+
+	failing := func(w util.BufWriter, source []byte, n ast.Node, entering bool) (bool, error) {
+		return false, errors.New("hook failed")
+	}
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRenderHook(failing)),
+		),
+	)
+	var buf bytes.Buffer
+	if err := mdTest.Convert([]byte(`x^2^`), &buf); err == nil || err.Error() != "hook failed" {
+		t.Errorf("Convert() error = %v, want hook failed", err)
+	}
+}
+
+func TestSuperscriptSymbolSubstitution(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSymbolSubstitution(nil)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Symbol substitution: trademark",
+			md:   `Acme^TM^`,
+			html: `<p>Acme&trade;</p>`,
+		},
+		{
+			desc: "Symbol substitution: service mark",
+			md:   `Acme^SM^`,
+			html: `<p>Acme&#8480;</p>`,
+		},
+		{
+			desc: "Symbol substitution: registered",
+			md:   `Acme^R^`,
+			html: `<p>Acme&reg;</p>`,
+		},
+		{
+			desc: "Symbol substitution: copyright",
+			md:   `Acme^C^`,
+			html: `<p>Acme&copy;</p>`,
+		},
+		{
+			desc: "Symbol substitution: unrecognized content falls back to sup",
+			md:   `Acme^tm^ x^2^`,
+			html: `<p>Acme<sup>tm</sup> x<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSymbolSubstitution(map[string]string{"o": "&ordm;"})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Symbol substitution: custom mapping replaces the defaults",
+			md:   `1^o^ Acme^TM^`,
+			html: `<p>1&ordm; Acme<sup>TM</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptTitleFunc(t *testing.T) {
+	power := func(content []byte) string {
+		if string(content) == "2" {
+			return "squared"
+		}
+		return ""
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTitleFunc(power), WithSourceAttribute()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Title func: tooltip from the content",
+			md:   `x^2^`,
+			html: `<p>x<sup data-md="^2^" title="squared">2</sup></p>`,
+		},
+		{
+			desc: "Title func: empty result emits no title",
+			md:   `x^3^`,
+			html: `<p>x<sup data-md="^3^">3</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTitleFunc(func(content []byte) string {
+				return `power "` + string(content) + `" <b>`
+			})),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("class"), Value: []byte("exp")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Title func: escaped and merged with node attributes",
+			md:   `x^n^`,
+			html: `<p>x<sup class="exp" title="power &quot;n&quot; &lt;b&gt;">n</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptXHTML(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithHardWraps(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "XHTML: superscripts alongside core XHTML output",
+			md:   "x^2^\ny^3^",
+			html: "<p>x<sup>2</sup><br />\ny<sup>3</sup></p>",
+		},
+	})
+
+	r := NewSuperscriptHTMLRenderer(html.WithXHTML())
+	mdTest = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithHardWraps(),
+			renderer.WithNodeRenderers(util.Prioritized(r, 100)),
+		),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(NewSuperscriptParser(), 100)),
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("class"), Value: []byte(`a"b`)},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "XHTML: manually constructed renderer with attributes",
+			md:   "x^2^\ny^3^",
+			html: "<p>x<sup class=\"a&quot;b\">2</sup><br />\ny<sup class=\"a&quot;b\">3</sup></p>",
+		},
+	})
+
+	if !r.(*SuperscriptHTMLRenderer).XHTML {
+		t.Error("renderer did not receive the XHTML setting")
+	}
+}
+
+func TestSuperscriptLinkFunc(t *testing.T) {
+	references := func(content []byte) (string, bool) {
+		if len(content) < 3 || content[0] != '[' || content[len(content)-1] != ']' {
+			return "", false
+		}
+		return "#ref-" + string(content[1:len(content)-1]), true
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLinkFunc(references)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Link func: reference marker is linked",
+			md:   `claim^[3]^`,
+			html: `<p>claim<sup><a href="#ref-3">[3]</a></sup></p>`,
+		},
+		{
+			desc: "Link func: other content is not linked",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Link func: href is escaped",
+			md:   `claim^["&<]^`,
+			html: `<p>claim<sup><a href="#ref-%22&amp;%3C">[&quot;&amp;&lt;]</a></sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLinkFunc(func(content []byte) (string, bool) {
+				return "javascript:alert(1)", true
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Link func: dangerous URLs are dropped",
+			md:   `x^2^`,
+			html: `<p>x<sup><a href="">2</a></sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptCRLF(t *testing.T) {
+	inputs := []string{
+		"x^2^\n",
+		"a^2^ + b^2^ = c^2^\nx = y^6^ + z^n+1^\n",
+		"x^2^\n^3^ at line start\n",
+		"a^2 + b^2\n= c^2^\n",
+		"x^2\n^ split across lines\n",
+		"a^2^^2^ + b^2^\n\nnext paragraph x^n^\n",
+		"hard break x^2^  \nthen y^3^\n",
+	}
+	for _, opts := range [][]SuperscriptOption{nil, {WithMultiline("")}} {
+		md := goldmark.New(goldmark.WithExtensions(NewSuperscript(opts...)))
+		for _, input := range inputs {
+			var lf, crlf bytes.Buffer
+			if err := md.Convert([]byte(input), &lf); err != nil {
+				t.Fatal(err)
+			}
+			if err := md.Convert([]byte(strings.ReplaceAll(input, "\n", "\r\n")), &crlf); err != nil {
+				t.Fatal(err)
+			}
+			if lf.String() != crlf.String() {
+				t.Errorf("CRLF output for %q (%d options) = %q, want %q", input, len(opts), crlf.String(), lf.String())
+			}
+		}
+	}
+}
+
+func TestSuperscriptRequireNonNumeric(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRequireNonNumeric()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Require non-numeric: purely numeric content is literal",
+			md:   `x^2^ and word^12^`,
+			html: `<p>x^2^ and word^12^</p>`,
+		},
+		{
+			desc: "Require non-numeric: mixed content",
+			md:   `x^2a^ and x^n+1^`,
+			html: `<p>x<sup>2a</sup> and x<sup>n+1</sup></p>`,
+		},
+		{
+			desc: "Require non-numeric: purely symbolic content",
+			md:   `Na^+^ and x^*^`,
+			html: `<p>Na<sup>+</sup> and x<sup>*</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSpanMode(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSpanMode("")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Span mode: default class",
+			md:   `x^2^`,
+			html: `<p>x<span class="superscript">2</span></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSpanMode("exp")),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("class"), Value: []byte("math")},
+				{Name: []byte("id"), Value: []byte("e1")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Span mode: custom class merged with node attributes",
+			md:   `E=mc^2^`,
+			html: `<p>E=mc<span class="math exp" id="e1">2</span></p>`,
+		},
+	})
+}
+
+func TestSuperscriptUnsafe(t *testing.T) {
+	testCases := []TestCase{
+		{
+			desc: "Unsafe: raw HTML inside a superscript is escaped",
+			md:   `x^<script>alert(1)</script>^`,
+			html: `<p>x<sup>&lt;script&gt;alert(1)&lt;/script&gt;</sup></p>`,
+		},
+		{
+			desc: "Unsafe: entities are still resolved",
+			md:   `x^&lt;b&gt;&times;^`,
+			html: `<p>x<sup>&lt;b&gt;×</sup></p>`,
+		},
+	}
+
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+	runTestCases(t, mdTest, append(testCases, TestCase{
+		desc: "Unsafe: raw HTML outside superscripts is omitted in safe mode",
+		md:   `a <b>x</b> y^2^`,
+		html: `<p>a <!-- raw HTML omitted -->x<!-- raw HTML omitted --> y<sup>2</sup></p>`,
+	}))
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
+	runTestCases(t, mdTest, append(testCases, TestCase{
+		desc: "Unsafe: raw HTML outside superscripts is kept in unsafe mode",
+		md:   `a <b>x</b> y^2^`,
+		html: `<p>a <b>x</b> y<sup>2</sup></p>`,
+	}))
+}
+
+func TestSuperscriptLiteralTripleCaret(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLiteralTripleCaret()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Literal triple caret: three carets",
+			md:   `a^^^b and x^^^2^`,
+			html: `<p>a^^^b and x^^^2^</p>`,
+		},
+		{
+			desc: "Literal triple caret: four carets",
+			md:   `a^^^^2^ and x^2^^^^`,
+			html: `<p>a^^^^2^ and x^2^^^^</p>`,
+		},
+		{
+			desc: "Literal triple caret: superscripts around a run",
+			md:   `x^2^ ^^^ y^3^`,
+			html: `<p>x<sup>2</sup> ^^^ y<sup>3</sup></p>`,
+		},
+		{
+			desc: "Literal triple caret: adjacent superscripts are unaffected",
+			md:   `a^2^^2^`,
+			html: `<p>a<sup>2</sup><sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Literal triple caret: default behavior",
+			md:   `x^^^2^`,
+			html: `<p>x^^<sup>2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptAllowedRunes(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAllowedRunes(unicode.IsDigit)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Allowed runes: digits only",
+			md:   `x^2^ and x^10^`,
+			html: `<p>x<sup>2</sup> and x<sup>10</sup></p>`,
+		},
+		{
+			desc: "Allowed runes: letters are rejected",
+			md:   `x^n^ and 1^st^`,
+			html: `<p>x^n^ and 1^st^</p>`,
+		},
+	})
+
+	exponent := func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsLetter(r) || strings.ContainsRune("+-()", r)
+	}
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAllowedRunes(exponent)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Allowed runes: custom set",
+			md:   `x^(n+1)^ and e^-x^`,
+			html: `<p>x<sup>(n+1)</sup> and e<sup>-x</sup></p>`,
+		},
+		{
+			desc: "Allowed runes: rune outside the set",
+			md:   `x^n*2^ and x^2/3^`,
+			html: `<p>x^n*2^ and x^2/3^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptAdjacentSeparator(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Adjacent separator: default inserts nothing",
+			md:   `a^2^^2^`,
+			html: `<p>a<sup>2</sup><sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAdjacentSeparator("&thinsp;")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Adjacent separator: between consecutive superscripts",
+			md:   `a^2^^2^`,
+			html: `<p>a<sup>2</sup>&thinsp;<sup>2</sup></p>`,
+		},
+		{
+			desc: "Adjacent separator: three in a row",
+			md:   `a^1^^2^^3^`,
+			html: `<p>a<sup>1</sup>&thinsp;<sup>2</sup>&thinsp;<sup>3</sup></p>`,
+		},
+		{
+			desc: "Adjacent separator: not between separated superscripts",
+			md:   `a^2^ b^2^`,
+			html: `<p>a<sup>2</sup> b<sup>2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptDoubleCaret(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Double caret: default parses only the inner single carets",
+			md:   `x^^2^^`,
+			html: `<p>x^<sup>2</sup>^</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDoubleCaret()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Double caret: explicit form",
+			md:   `x^^2^^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Double caret: single carets inside",
+			md:   `x^^a^b^^`,
+			html: `<p>x<sup>a^b</sup></p>`,
+		},
+		{
+			desc: "Double caret: single-caret form still works",
+			md:   `x^2^ and y^^3^^`,
+			html: `<p>x<sup>2</sup> and y<sup>3</sup></p>`,
+		},
+		{
+			desc: "Double caret: whitespace is not allowed",
+			md:   `x^^a b^^`,
+			html: `<p>x^^a b^^</p>`,
+		},
+		{
+			desc: "Double caret: preceded by whitespace",
+			md:   `x ^^2^^`,
+			html: `<p>x ^^2^^</p>`,
+		},
+		{
+			desc: "Double caret: adjacent single-caret superscripts",
+			md:   `a^2^^2^`,
+			html: `<p>a<sup>2</sup><sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDoubleCaretOnly()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Double caret only: explicit form",
+			md:   `x^^2^^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Double caret only: single-caret form is literal",
+			md:   `x^2^ and y^^3^^`,
+			html: `<p>x^2^ and y<sup>3</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptImplicitClose(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Implicit close: default leaves unclosed carets literal",
+			md:   `x^2 and x^23`,
+			html: `<p>x^2 and x^23</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithImplicitClose(nil)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Implicit close: digit run",
+			md:   `x^23 and y^2`,
+			html: `<p>x<sup>23</sup> and y<sup>2</sup></p>`,
+		},
+		{
+			desc: "Implicit close: run ends at punctuation",
+			md:   `area in m^2, volume in m^3.`,
+			html: `<p>area in m<sup>2</sup>, volume in m<sup>3</sup>.</p>`,
+		},
+		{
+			desc: "Implicit close: run ends at a letter",
+			md:   `x^2n`,
+			html: `<p>x<sup>2</sup>n</p>`,
+		},
+		{
+			desc: "Implicit close: no matching run",
+			md:   `x^n and x^`,
+			html: `<p>x^n and x^</p>`,
+		},
+		{
+			desc: "Implicit close: closed superscripts are unaffected",
+			md:   `x^2 and y^n+1^`,
+			html: `<p>x<sup>2</sup> and y<sup>n+1</sup></p>`,
+		},
+		{
+			desc: "Implicit close: preceded by whitespace",
+			md:   `x ^2`,
+			html: `<p>x ^2</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithImplicitClose(func(r rune) bool {
+				return unicode.IsLetter(r) || unicode.IsDigit(r)
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Implicit close: custom rune class",
+			md:   `x^2n+1`,
+			html: `<p>x<sup>2n</sup>+1</p>`,
+		},
+	})
+}
+
+func TestSuperscriptDump(t *testing.T) {
+	source := []byte("x^n+1^")
+	doc := goldmark.New(goldmark.WithExtensions(NewSuperscript())).Parser().Parse(text.NewReader(source))
+	sup := doc.FirstChild().LastChild()
+	if sup.Kind() != KindSuperscript {
+		t.Fatalf("last inline is %v, want %v", sup.Kind(), KindSuperscript)
+	}
+
+	// ast.DumpHelper prints to standard output.
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	sup.Dump(source, 0)
+	os.Stdout = stdout
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(out), `Content: n+1`) {
+		t.Errorf("Dump() output does not contain the content:\n%s", out)
+	}
+}
+
+// Code spans are parsed left to right before any caret inside them is reached, so
+// goldmark keeps their content opaque without help from the superscript parser.
+func TestSuperscriptCodeSpans(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Code spans: carets inside stay literal",
+			md:   "`x^2^`",
+			html: `<p><code>x^2^</code></p>`,
+		},
+		{
+			desc: "Code spans: longer delimiters",
+			md:   "`` x^2^ and `y^3^` ``",
+			html: "<p><code>x^2^ and `y^3^`</code></p>",
+		},
+		{
+			desc: "Code spans: superscript right after a code span",
+			md:   "`x`^2^",
+			html: `<p><code>x</code><sup>2</sup></p>`,
+		},
+		{
+			desc: "Code spans: caret closing inside a code span",
+			md:   "x^2 `y^` z",
+			html: `<p>x^2 <code>y^</code> z</p>`,
+		},
+		{
+			desc: "Code spans: superscripts around a code span",
+			md:   "a^1^ `b^2^` c^3^",
+			html: `<p>a<sup>1</sup> <code>b^2^</code> c<sup>3</sup></p>`,
+		},
+		{
+			desc: "Code spans: code span across the closing caret",
+			md:   "x^`y^` and a^b`^`",
+			html: `<p>x^<code>y^</code> and a^b<code>^</code></p>`,
+		},
+		{
+			desc: "Code spans: backticks within the content",
+			md:   "x^`y`^ and x^a`b^",
+			html: "<p>x<sup>`y`</sup> and x<sup>a`b</sup></p>",
+		},
+	})
+}
+
+// recordingParser and recordingRenderer keep the options added by an extension.
+type recordingParser struct {
+	parser.Parser
+	opts []parser.Option
+}
+
+func (p *recordingParser) AddOptions(opts ...parser.Option) {
+	p.opts = append(p.opts, opts...)
+}
+
+type recordingRenderer struct {
+	renderer.Renderer
+	opts []renderer.Option
+}
+
+func (r *recordingRenderer) AddOptions(opts ...renderer.Option) {
+	r.opts = append(r.opts, opts...)
+}
+
+func TestSuperscriptExtendPriority(t *testing.T) {
+	md := goldmark.New()
+	p := &recordingParser{Parser: md.Parser()}
+	r := &recordingRenderer{Renderer: md.Renderer()}
+	md.SetParser(p)
+	md.SetRenderer(r)
+	NewSuperscript(WithAutoID("")).Extend(md)
+
+	pc := parser.NewConfig()
+	for _, opt := range p.opts {
+		opt.SetParserOption(pc)
+	}
+	rc := renderer.NewConfig()
+	for _, opt := range r.opts {
+		opt.SetConfig(rc)
+	}
+
+	for _, v := range pc.InlineParsers {
+		if v.Priority != DefaultParserPriority {
+			t.Errorf("inline parser priority = %d, want %d", v.Priority, DefaultParserPriority)
+		}
+	}
+	for _, v := range pc.ASTTransformers {
+		if v.Priority != DefaultParserPriority {
+			t.Errorf("AST transformer priority = %d, want %d", v.Priority, DefaultParserPriority)
+		}
+	}
+	for _, v := range rc.NodeRenderers {
+		if v.Priority != DefaultRendererPriority {
+			t.Errorf("node renderer priority = %d, want %d", v.Priority, DefaultRendererPriority)
+		}
+	}
+	if len(pc.InlineParsers) != 1 || len(pc.ASTTransformers) != 1 || len(rc.NodeRenderers) != 1 {
+		t.Errorf("Extend registered %d parsers, %d transformers and %d renderers, want 1 each",
+			len(pc.InlineParsers), len(pc.ASTTransformers), len(rc.NodeRenderers))
+	}
+}
+
+func TestSuperscriptEscapeContentEntities(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Escape content entities: default passes entities through",
+			md:   `a^2&times;n^`,
+			html: `<p>a<sup>2×n</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithEscapeContentEntities()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Escape content entities: named entity is shown literally",
+			md:   `a^2&times;n^`,
+			html: `<p>a<sup>2&amp;times;n</sup></p>`,
+		},
+		{
+			desc: "Escape content entities: numeric reference is shown literally",
+			md:   `a^&#215;^`,
+			html: `<p>a<sup>&amp;#215;</sup></p>`,
+		},
+		{
+			desc: "Escape content entities: other characters are still escaped",
+			md:   `a^<b>^ and a^x\*y^`,
+			html: `<p>a<sup>&lt;b&gt;</sup> and a<sup>x*y</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptCloseOnBoundary(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCloseOnBoundary(nil)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Close on boundary: single sign",
+			md:   `Na^+ and Cl^-`,
+			html: `<p>Na<sup>+</sup> and Cl<sup>-</sup></p>`,
+		},
+		{
+			desc: "Close on boundary: charge with digits",
+			md:   `SO~4~^2- ions, or SO^2-.`,
+			html: `<p>SO~4~<sup>2-</sup> ions, or SO<sup>2-</sup>.</p>`,
+		},
+		{
+			desc: "Close on boundary: run followed by a word character",
+			md:   `Na^+a and x^2b`,
+			html: `<p>Na^+a and x^2b</p>`,
+		},
+		{
+			desc: "Close on boundary: bare digits stay literal",
+			md:   `x^2 and Fe^3+ and Fe^2+^`,
+			html: `<p>x^2 and Fe<sup>3+</sup> and Fe<sup>2+</sup></p>`,
+		},
+		{
+			desc: "Close on boundary: closed superscripts are unaffected",
+			md:   `Na^+ and x^n+1^`,
+			html: `<p>Na<sup>+</sup> and x<sup>n+1</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCloseOnBoundary(func(r rune) bool {
+				return r == '+' || r == '-'
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Close on boundary: digits outside the class stay literal",
+			md:   `x^2 and Na^+`,
+			html: `<p>x^2 and Na<sup>+</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Close on boundary: default leaves unclosed carets literal",
+			md:   `Na^+ and SO^2-`,
+			html: `<p>Na^+ and SO^2-</p>`,
+		},
+	})
+}
+
+func TestSuperscriptSROnly(t *testing.T) {
+	power := func(content []byte) string {
+		if !isNumeric(content) {
+			return ""
+		}
+		return " to the power of " + string(content)
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSROnly("", power)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "SR only: expansion span",
+			md:   `x^2^`,
+			html: `<p>x<sup>2<span class="sr-only"> to the power of 2</span></sup></p>`,
+		},
+		{
+			desc: "SR only: empty expansion",
+			md:   `x^n^`,
+			html: `<p>x<sup>n</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSROnly("visually-hidden", func(content []byte) string {
+				return " <" + string(content) + ">"
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "SR only: custom class and escaped text",
+			md:   `x^n^`,
+			html: `<p>x<sup>n<span class="visually-hidden"> &lt;n&gt;</span></sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptNormalize(t *testing.T) {
+	const composed, decomposed = "x^caf\u00e9^", "x^cafe\u0301^"
+
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Normalize: default keeps decomposed content",
+			md:   decomposed,
+			html: "<p>x<sup>cafe\u0301</sup></p>",
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithNormalize(norm.NFC)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Normalize: NFC input",
+			md:   composed,
+			html: "<p>x<sup>caf\u00e9</sup></p>",
+		},
+		{
+			desc: "Normalize: NFD input",
+			md:   decomposed,
+			html: "<p>x<sup>caf\u00e9</sup></p>",
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithNormalize(norm.NFD)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Normalize: NFD form",
+			md:   composed,
+			html: "<p>x<sup>cafe\u0301</sup></p>",
+		},
+	})
+}
+
+// The closing caret may be the last byte of the source, with no line ending after it.
+func TestSuperscriptEndOfInput(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "End of input: superscript is the entire input",
+			md:   "x^2^",
+			html: "<p>x<sup>2</sup></p>",
+		},
+		{
+			desc: "End of input: superscript ends the final line",
+			md:   "first line\nsecond x^2^",
+			html: "<p>first line\nsecond x<sup>2</sup></p>",
+		},
+		{
+			desc: "End of input: final line of a later paragraph",
+			md:   "first\n\nx^n+1^",
+			html: "<p>first</p>\n<p>x<sup>n+1</sup></p>",
+		},
+		{
+			desc: "End of input: unclosed caret is literal",
+			md:   "x^2",
+			html: "<p>x^2</p>",
+		},
+		{
+			desc: "End of input: trailing caret is literal",
+			md:   "x^",
+			html: "<p>x^</p>",
+		},
+	})
+}
+
+func TestSuperscriptItemprop(t *testing.T) {
+	if !SuperscriptAttributeFilter.Contains([]byte("itemprop")) {
+		t.Fatal("SuperscriptAttributeFilter does not allow itemprop")
+	}
+
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithItemprop("exponent")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Itemprop: attribute is added",
+			md:   `x^2^`,
+			html: `<p>x<sup itemprop="exponent">2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithItemprop("exponent"), WithDeterministicAttributes()),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("itemprop"), Value: []byte("charge")},
+				{Name: []byte("class"), Value: []byte("ion")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Itemprop: node attribute wins",
+			md:   `Na^+^`,
+			html: `<p>Na<sup class="ion" itemprop="charge">+</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSignNormalization(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Sign normalization: default keeps the hyphen",
+			md:   `x^-2^`,
+			html: `<p>x<sup>-2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSignNormalization()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Sign normalization: leading hyphen",
+			md:   `x^-2^ and e^-x&amp;y^`,
+			html: "<p>x<sup>−2</sup> and e<sup>−x&amp;y</sup></p>",
+		},
+		{
+			desc: "Sign normalization: other hyphens and signs are unchanged",
+			md:   `x^+2^ and x^n-1^`,
+			html: `<p>x<sup>+2</sup> and x<sup>n-1</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSignNormalization(), WithContentEntities(EntitiesNamed)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Sign normalization: with content entities",
+			md:   `x^-2^`,
+			html: `<p>x<sup>&minus;&#x32;</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptLang(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLang("fr")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Lang: attribute is added",
+			md:   `le 1^er^ et la 2^e^`,
+			html: `<p>le 1<sup lang="fr">er</sup> et la 2<sup lang="fr">e</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLang("fr")),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("lang"), Value: []byte("es")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Lang: node attribute wins",
+			md:   `1^o^`,
+			html: `<p>1<sup lang="es">o</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptInlineContent(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Inline content: default keeps content flat",
+			md:   `x^*2*^`,
+			html: `<p>x<sup>*2*</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithInlineContent(), WithDoubleCaret()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Inline content: emphasis",
+			md:   `x^*2*^ and y^**n**+1^`,
+			html: `<p>x<sup><em>2</em></sup> and y<sup><strong>n</strong>+1</sup></p>`,
+		},
+		{
+			desc: "Inline content: inline link",
+			md:   `claim^[3](#ref-3)^`,
+			html: `<p>claim<sup><a href="#ref-3">3</a></sup></p>`,
+		},
+		{
+			desc: "Inline content: reference link",
+			md:   "claim^[3]^\n\n[3]: https://example.com/3",
+			html: `<p>claim<sup><a href="https://example.com/3">3</a></sup></p>`,
+		},
+		{
+			desc: "Inline content: code span",
+			md:   "x^`n`^",
+			html: `<p>x<sup><code>n</code></sup></p>`,
+		},
+		{
+			desc: "Inline content: nested superscripts stay flat",
+			md:   `x^^a^b^c^^`,
+			html: `<p>x<sup>a^b^c</sup></p>`,
+		},
+		{
+			desc: "Inline content: block syntax stays flat",
+			md:   `x^>a^`,
+			html: `<p>x<sup>&gt;a</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptWithoutStrikethroughDeference(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Strikethrough deference: default skips the doubled caret",
+			md:   `a^^2^`,
+			html: `<p>a^<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithoutStrikethroughDeference()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Strikethrough deference: disabled",
+			md:   `a^^2^`,
+			html: `<p>a<sup>^2</sup></p>`,
+		},
+		{
+			desc: "Strikethrough deference: disabled with a doubled closer",
+			md:   `a^^x^^`,
+			html: `<p>a<sup>^x</sup>^</p>`,
+		},
+		{
+			desc: "Strikethrough deference: disabled, single carets unaffected",
+			md:   `x^2^ and b^^`,
+			html: `<p>x<sup>2</sup> and b^^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptQuotedContent(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Quoted content: default keeps spaces literal",
+			md:   `x^"a b"^`,
+			html: `<p>x^&quot;a b&quot;^</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithQuotedContent(), WithTrimTrailingPunctuation()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Quoted content: spaces inside quotes",
+			md:   `x^"a b"^ and y^"n + 1"^`,
+			html: `<p>x<sup>a b</sup> and y<sup>n + 1</sup></p>`,
+		},
+		{
+			desc: "Quoted content: quotes without spaces are stripped",
+			md:   `x^"ab."^`,
+			html: `<p>x<sup>ab.</sup></p>`,
+		},
+		{
+			desc: "Quoted content: unbalanced quote",
+			md:   `x^"a b^ and y^a b"^`,
+			html: `<p>x^&quot;a b^ and y^a b&quot;^</p>`,
+		},
+		{
+			desc: "Quoted content: blank quotes",
+			md:   `x^" "^`,
+			html: `<p>x^&quot; &quot;^</p>`,
+		},
+		{
+			desc: "Quoted content: normal content",
+			md:   `x^2^ and x^"2"^ and x^2.^`,
+			html: `<p>x<sup>2</sup> and x<sup>2</sup> and x<sup>2</sup>.</p>`,
+		},
+	})
+}
+
+func TestSuperscriptMinContentLength(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMinContentLength(2)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Min content length: shorter content is literal",
+			md:   `x^2^ and a^b^`,
+			html: `<p>x^2^ and a^b^</p>`,
+		},
+		{
+			desc: "Min content length: at the boundary",
+			md:   `x^10^ and a^th^`,
+			html: `<p>x<sup>10</sup> and a<sup>th</sup></p>`,
+		},
+		{
+			desc: "Min content length: counted in runes",
+			md:   `x^°^ and x^°C^`,
+			html: `<p>x^°^ and x<sup>°C</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMinContentLength(1)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Min content length: default accepts single characters",
+			md:   `x^2^ and x^°^`,
+			html: `<p>x<sup>2</sup> and x<sup>°</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptClassFunc(t *testing.T) {
+	classify := func(content []byte) string {
+		switch {
+		case isNumeric(content):
+			return "num"
+		case bytes.Equal(content, []byte("*")):
+			return ""
+		default:
+			return "label"
+		}
+	}
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithClassFunc(classify)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Class func: numeric and non-numeric content",
+			md:   `x^2^ and Acme^TM^`,
+			html: `<p>x<sup class="num">2</sup> and Acme<sup class="label">TM</sup></p>`,
+		},
+		{
+			desc: "Class func: empty class",
+			md:   `x^*^`,
+			html: `<p>x<sup>*</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithClassFunc(classify), WithSpanMode("")),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(attributeTransformer{
+				{Name: []byte("class"), Value: []byte("math")},
+			}, 100)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Class func: joined with other classes",
+			md:   `x^2^`,
+			html: `<p>x<span class="math superscript num">2</span></p>`,
+		},
+	})
+}
+
+// A caret that is the last character of a line must never open a superscript,
+// whatever follows it and whichever options are set.
+func TestSuperscriptCaretAtEndOfLine(t *testing.T) {
+	inputs := []string{
+		"x^",
+		"x^\nnext",
+		"x^\r\nnext",
+		"x^\r\n",
+		"x^ \nnext",
+		"x^ ",
+		"x^\t\r\n",
+	}
+	optionSets := [][]SuperscriptOption{
+		nil,
+		{WithMultiline(" ")},
+		{WithImplicitClose(nil)},
+		{WithCloseOnBoundary(nil)},
+		{WithDoubleCaret()},
+		{WithoutStrikethroughDeference()},
+		{WithStrict(), WithStats(), WithSymmetricOnly()},
+		{WithCloseDelimiter('°')},
+	}
+
+	for _, opts := range optionSets {
+		mdTest := goldmark.New(goldmark.WithExtensions(NewSuperscript(opts...)))
+		for _, input := range inputs {
+			var buf bytes.Buffer
+			if err := mdTest.Convert([]byte(input), &buf); err != nil {
+				t.Fatalf("Convert(%q) error = %v", input, err)
+			}
+			if strings.Contains(buf.String(), "<sup") {
+				t.Errorf("Convert(%q) with %d options = %q, want no superscript", input, len(opts), buf.String())
+			}
+		}
+	}
+}
+
+func TestSuperscriptFootnoteSafeClass(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithFootnoteSafeClass("")),
+			extension.Footnote,
+		),
+	)
+
+	var buf bytes.Buffer
+	if err := mdTest.Convert([]byte("word^1^ and word[^1]\n\n[^1]: A note."), &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `word<sup class="superscript">1</sup>`) {
+		t.Errorf("superscript does not carry the class:\n%s", out)
+	}
+	if !strings.Contains(out, `<sup id="fnref:1">`) {
+		t.Errorf("footnote reference is missing or carries the class:\n%s", out)
+	}
+	if strings.Count(out, `class="superscript"`) != 1 {
+		t.Errorf("class appears more than once:\n%s", out)
+	}
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithFootnoteSafeClass("md-sup")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Footnote safe class: custom class",
+			md:   `x^2^`,
+			html: `<p>x<sup class="md-sup">2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptTower(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTower()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Tower: two levels",
+			md:   `2^2^2^`,
+			html: `<p>2<sup>2<sup>2</sup></sup></p>`,
+		},
+		{
+			desc: "Tower: three levels",
+			md:   `e^x^2^n^`,
+			html: `<p>e<sup>x<sup>2<sup>n</sup></sup></sup></p>`,
+		},
+		{
+			desc: "Tower: text after the chain stays outside",
+			md:   `2^2^2^, then x^2^`,
+			html: `<p>2<sup>2<sup>2</sup></sup>, then x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Tower: a doubled caret is not a chain",
+			md:   `2^^2^2^`,
+			html: `<p>2^<sup>2</sup>2^</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Tower: flat without the option",
+			md:   `2^2^2^`,
+			html: `<p>2<sup>2</sup>2^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptShowDelimiters(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithShowDelimiters()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Show delimiters: plain",
+			md:   `x^2^ and 1^st^`,
+			html: `<p>x<sup>^2^</sup> and 1<sup>^st^</sup></p>`,
+		},
+		{
+			desc: "Show delimiters: rejected carets are unchanged",
+			md:   `x ^2^ and y^`,
+			html: `<p>x ^2^ and y^</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiterClass("delim")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Show delimiters: wrapped in a span",
+			md:   `x^2^`,
+			html: `<p>x<sup><span class="delim">^</span>2<span class="delim">^</span></sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithShowDelimiters(), WithDoubleCaret(), WithImplicitClose(nil)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Show delimiters: double caret form",
+			md:   `x^^a^b^^`,
+			html: `<p>x<sup>^^a^b^^</sup></p>`,
+		},
+		{
+			desc: "Show delimiters: implicitly closed",
+			md:   `x^2 and y`,
+			html: `<p>x<sup>^2</sup> and y</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithShowDelimiters(), WithTower()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Show delimiters: tower",
+			md:   `2^2^2^`,
+			html: `<p>2<sup>^2<sup>^2^</sup></sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSkipCJKContext(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSkipCJKContext()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Skip CJK context: Han on both sides",
+			md:   `中文^字^中文`,
+			html: `<p>中文^字^中文</p>`,
+		},
+		{
+			desc: "Skip CJK context: Hiragana and Katakana",
+			md:   `ひら^カナ^です`,
+			html: `<p>ひら^カナ^です</p>`,
+		},
+		{
+			desc: "Skip CJK context: Latin content after CJK",
+			md:   `面积10m^2^平方米`,
+			html: `<p>面积10m<sup>2</sup>平方米</p>`,
+		},
+		{
+			desc: "Skip CJK context: CJK content after Latin",
+			md:   `x^中^ and 字^2^`,
+			html: `<p>x<sup>中</sup> and 字<sup>2</sup></p>`,
+		},
+		{
+			desc: "Skip CJK context: Latin text",
+			md:   `E=mc^2^`,
+			html: `<p>E=mc<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Skip CJK context: parsed without the option",
+			md:   `中文^字^中文`,
+			html: `<p>中文<sup>字</sup>中文</p>`,
+		},
+	})
+}
+
+func TestSuperscriptZeroWidth(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTrimZeroWidth()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Trim zero width: zero-width space inside content",
+			md:   "x^1\u200B0^",
+			html: `<p>x<sup>10</sup></p>`,
+		},
+		{
+			desc: "Trim zero width: byte order mark and joiner",
+			md:   "x^\uFEFFn\u200D^",
+			html: `<p>x<sup>n</sup></p>`,
+		},
+		{
+			desc: "Trim zero width: nothing left",
+			md:   "x^\u200B^",
+			html: "<p>x^\u200B^</p>",
+		},
+		{
+			desc: "Trim zero width: plain content",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRejectZeroWidth()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Reject zero width: zero-width space inside content",
+			md:   "x^1\u200B0^ and x^2^",
+			html: "<p>x^1\u200B0^ and x<sup>2</sup></p>",
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Zero width: kept by default",
+			md:   "x^1\u200B0^",
+			html: "<p>x<sup>1\u200B0</sup></p>",
+		},
+	})
+}
+
+func TestSuperscriptAttributeEscaping(t *testing.T) {
+	echo := func(content []byte) string { return string(content) }
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSourceAttribute(), WithTitleFunc(echo), WithClassFunc(echo)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Attribute escaping: double quotes",
+			md:   `x^a"onclick="alert(1)^`,
+			html: `<p>x<sup class="a&quot;onclick=&quot;alert(1)" data-md="^a&quot;onclick=&quot;alert(1)^" title="a&quot;onclick=&quot;alert(1)">a&quot;onclick=&quot;alert(1)</sup></p>`,
+		},
+		{
+			desc: "Attribute escaping: angle brackets and ampersands",
+			md:   `x^<b>&amp;^`,
+			html: `<p>x<sup class="&lt;b&gt;&amp;amp;" data-md="^&lt;b&gt;&amp;amp;^" title="&lt;b&gt;&amp;amp;">&lt;b&gt;&amp;</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptPairedPriority(t *testing.T) {
+	testCases := []struct {
+		desc string
+		base int
+		html string
+	}{
+		{
+			desc: "Paired priority: superscripts first at a shared trigger",
+			base: SubscriptParserPriority - 1,
+			html: `<p>a<sup>2</sup><sup>3</sup> and x<sup>i</sup></p>`,
+		},
+		{
+			desc: "Paired priority: subscripts first at a shared trigger",
+			base: SubscriptParserPriority + 1,
+			html: `<p>a<sup>2</sup><sub>3</sub> and x<sub>i</sub></p>`,
+		},
+	}
+
+	// At the shared ~ trigger the parser with the lower priority is tried first, whatever
+	// order the extensions are registered in
+	for _, tc := range testCases {
+		sup := NewSuperscript(WithPairedPriority(tc.base), WithDelimiters('^', '~'))
+		for _, extensions := range [][]goldmark.Extender{
+			{sup, subscript.NewSubscript()},
+			{subscript.NewSubscript(), sup},
+		} {
+			runTestCases(t, goldmark.New(goldmark.WithExtensions(extensions...)), []TestCase{
+				{
+					desc: tc.desc,
+					md:   `a^2^~3~ and x~i~`,
+					html: tc.html,
+				},
+			})
+		}
+	}
+}
+
+func TestSuperscriptLinkDestinations(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Link destinations: carets in the URL are kept",
+			md:   `[text](http://a^b^c)`,
+			html: `<p><a href="http://a%5Eb%5Ec">text</a></p>`,
+		},
+		{
+			desc: "Link destinations: superscript in the label",
+			md:   `[x^2^](url)`,
+			html: `<p><a href="url">x<sup>2</sup></a></p>`,
+		},
+		{
+			desc: "Link destinations: both",
+			md:   `[x^2^](http://a^b^c "t^1^")`,
+			html: `<p><a href="http://a%5Eb%5Ec" title="t^1^">x<sup>2</sup></a></p>`,
+		},
+		{
+			desc: "Link destinations: superscript opened in the label",
+			md:   `[a^b](c^d)`,
+			html: `<p><a href="c%5Ed">a^b</a></p>`,
+		},
+		{
+			desc: "Link destinations: superscript opened before the label",
+			md:   `x^a[b^](c)`,
+			html: `<p>x^a<a href="c">b^</a></p>`,
+		},
+		{
+			desc: "Link destinations: reference label",
+			md:   "[a^b][x^y]\n\n[x^y]: u",
+			html: `<p><a href="u">a^b</a></p>`,
+		},
+		{
+			desc: "Link destinations: balanced brackets in content",
+			md:   `x^[1]^ and x^\]^`,
+			html: `<p>x<sup>[1]</sup> and x<sup>]</sup></p>`,
+		},
+		{
+			desc: "Link destinations: closing bracket without a label",
+			md:   `x^a]^ and f(x)^a]^ and b^<]^`,
+			html: `<p>x<sup>a]</sup> and f(x)<sup>a]</sup> and b<sup>&lt;]</sup></p>`,
+		},
+		{
+			desc: "Link destinations: closing bracket of a label opened earlier",
+			md:   "[see `[` x^a](u)^",
+			html: `<p><a href="u">see <code>[</code> x^a</a>^</p>`,
+		},
+		{
+			desc: "Link destinations: autolink",
+			md:   `<http://a^b^c>`,
+			html: `<p><a href="http://a%5Eb%5Ec">http://a^b^c</a></p>`,
+		},
+		{
+			desc: "Link destinations: reference definition",
+			md:   "[x^2^][ref]\n\n[ref]: http://a^b^c",
+			html: `<p><a href="http://a%5Eb%5Ec">x<sup>2</sup></a></p>`,
+		},
+	})
+}
+
+func TestSuperscriptRenderTransform(t *testing.T) {
+	source := []byte(`x^n+1^ and 1^st^`)
+	doc := goldmark.New(goldmark.WithExtensions(NewSuperscript())).Parser().Parse(text.NewReader(source))
+
+	testCases := []struct {
+		desc      string
+		transform func(content []byte) []byte
+		want      string
+	}{
+		{
+			desc:      "Render transform: upper case",
+			transform: bytes.ToUpper,
+			want:      "<p>x<sup>N+1</sup> and 1<sup>ST</sup></p>\n",
+		},
+		{
+			desc: "Render transform: wrapped and escaped",
+			transform: func(content []byte) []byte {
+				return append(append([]byte("<"), content...), '>')
+			},
+			want: "<p>x<sup>&lt;n+1&gt;</sup> and 1<sup>&lt;st&gt;</sup></p>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := renderer.NewRenderer(renderer.WithNodeRenderers(
+				util.Prioritized(html.NewRenderer(), 1000),
+				util.Prioritized(NewSuperscriptHTMLRendererWithConfig(NewConfig(WithRenderTransform(tc.transform))), 100),
+			))
+			var buf bytes.Buffer
+			if err := r.Render(&buf, source, doc); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if got, want := string(source), `x^n+1^ and 1^st^`; got != want {
+		t.Errorf("source = %q after rendering, want %q", got, want)
+	}
+}
+
+func TestSuperscriptLineContinuation(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithLineContinuation()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Line continuation: backslash-continued superscript",
+			md:   "e^x+y+\\\nz^ done",
+			html: `<p>e<sup>x+y+z</sup> done</p>`,
+		},
+		{
+			desc: "Line continuation: several lines",
+			md:   "e^a\\\nb\\\nc^",
+			html: `<p>e<sup>abc</sup></p>`,
+		},
+		{
+			desc: "Line continuation: normal superscript",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Line continuation: soft line break still ends the search",
+			md:   "e^x\ny^",
+			html: "<p>e^x\ny^</p>",
+		},
+		{
+			desc: "Line continuation: no closing caret",
+			md:   "e^x\\\ny",
+			html: "<p>e^x<br>\ny</p>",
+		},
+	})
+}
+
+func TestSuperscriptBEM(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithBEM("formula")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "BEM: element class",
+			md:   `x^2^`,
+			html: `<p>x<sup class="formula__sup">2</sup></p>`,
+		},
+	})
+
+	classify := func(content []byte) string {
+		if isNumeric(content) {
+			return "numeric"
+		}
+		return ""
+	}
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithBEM("formula"), WithClassFunc(classify)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "BEM: modifier from the classifier",
+			md:   `x^2^ and 1^st^`,
+			html: `<p>x<sup class="formula__sup formula__sup--numeric">2</sup> and 1<sup class="formula__sup">st</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptDelimiterFunc(t *testing.T) {
+	open := func([]byte) string { return "⟦sup⟧" }
+	closing := func([]byte) string { return "⟦/sup⟧" }
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiterFunc(open, closing), WithSourceAttribute()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Delimiter func: static markers",
+			md:   `x^2^ and 1^a<b^`,
+			html: `<p>x⟦sup⟧2⟦/sup⟧ and 1⟦sup⟧a&lt;b⟦/sup⟧</p>`,
+		},
+	})
+
+	kind := func(content []byte) string {
+		if isNumeric(content) {
+			return `<exp kind="number">`
+		}
+		return `<exp kind="text">`
+	}
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiterFunc(kind, func([]byte) string { return "</exp>" })),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Delimiter func: content-dependent markers",
+			md:   `x^2^ and 1^st^`,
+			html: `<p>x<exp kind="number">2</exp> and 1<exp kind="text">st</exp></p>`,
+		},
+	})
+}
+
+func TestSuperscriptEntityOnlyContent(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(),
+		),
+	)
+
+	// goldmark resolves character references when rendering text
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Entity content: named reference",
+			md:   `a^&deg;^`,
+			html: `<p>a<sup>°</sup></p>`,
+		},
+		{
+			desc: "Entity content: hexadecimal reference",
+			md:   `a^&#x1F600;^`,
+			html: `<p>a<sup>😀</sup></p>`,
+		},
+		{
+			desc: "Entity content: ampersand",
+			md:   `a^&amp;^`,
+			html: `<p>a<sup>&amp;</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithTrimTrailingPunctuation(), WithMinContentLength(1), WithRequireNonNumeric()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Entity content: semicolon is not trimmed",
+			md:   `a^&deg;^ and b^x;^`,
+			html: `<p>a<sup>°</sup> and b<sup>x</sup>;</p>`,
+		},
+		{
+			desc: "Entity content: validated as the character it stands for",
+			md:   `a^&#x32;^ and a^&amp;^`,
+			html: `<p>a^2^ and a<sup>&amp;</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithMinContentLength(2), WithUnicodeOutput()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Entity content: counted as one character",
+			md:   `a^&deg;^ and a^&deg;C^`,
+			html: `<p>a^°^ and a<sup>°C</sup></p>`,
+		},
+		{
+			desc: "Entity content: Unicode output",
+			md:   `x^&#x31;&#x30;^`,
+			html: `<p>x¹⁰</p>`,
+		},
+	})
+}
+
+func TestSuperscriptPositionAttributes(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithPositionAttributes()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Position attributes: offsets of the content",
+			md:   "x^2^ and y^n+1^",
+			html: `<p>x<sup data-start="2" data-stop="3">2</sup> and y<sup data-start="11" data-stop="14">n+1</sup></p>`,
+		},
+		{
+			desc: "Position attributes: later lines",
+			md:   "> quote\n> e^x^",
+			html: "<blockquote>\n<p>quote\ne<sup data-start=\"12\" data-stop=\"13\">x</sup></p>\n</blockquote>",
+		},
+	})
+
+	// The offsets point at the content in the input
+	source := []byte("a^1^ b^\\*^\n\n- c^x+y^")
+	doc := mdTest.Parser().Parse(text.NewReader(source))
+	_ = Walk(doc, source, func(n *Node) error {
+		start, stop, ok := contentPosition(n)
+		if got, want := string(source[start:stop]), string(nodeContent(n, source)); !ok || got != want {
+			t.Errorf("source[%d:%d] = %q, want %q", start, stop, got, want)
+		}
+		return nil
+	})
+}
+
+func TestSuperscriptParenImplicitClose(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithParenImplicitClose(false)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Paren implicit close: group after the caret",
+			md:   `x^(n+1) and y`,
+			html: `<p>x<sup>n+1</sup> and y</p>`,
+		},
+		{
+			desc: "Paren implicit close: nested parentheses",
+			md:   `e^(-(x-m)/2).`,
+			html: `<p>e<sup>-(x-m)/2</sup>.</p>`,
+		},
+		{
+			desc: "Paren implicit close: unbalanced",
+			md:   `x^(n+1 and x^((n)`,
+			html: `<p>x^(n+1 and x^((n)</p>`,
+		},
+		{
+			desc: "Paren implicit close: explicit closing caret",
+			md:   `x^(n+1)^`,
+			html: `<p>x<sup>(n+1)</sup></p>`,
+		},
+		{
+			desc: "Paren implicit close: later caret on the line",
+			md:   `x^(n+1) and y^2^`,
+			html: `<p>x<sup>n+1</sup> and y<sup>2</sup></p>`,
+		},
+		{
+			desc: "Paren implicit close: empty group",
+			md:   `x^() and x ^(n)`,
+			html: `<p>x^() and x ^(n)</p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithParenImplicitClose(true)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Paren implicit close: parentheses kept",
+			md:   `x^(n+1)`,
+			html: `<p>x<sup>(n+1)</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptNumeralMapping(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithNumeralMapping(ArabicIndicDigits)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Numeral mapping: Arabic-Indic digits",
+			md:   `x^23^ and n^2k+1^`,
+			html: `<p>x<sup>٢٣</sup> and n<sup>٢k+١</sup></p>`,
+		},
+	})
+
+	custom := map[rune]rune{'2': 'Ⅱ', '3': 'Ⅲ', 'a': 'b'}
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithNumeralMapping(custom)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Numeral mapping: custom set, non-digits pass through",
+			md:   `x^23^ and x^41a^`,
+			html: `<p>x<sup>ⅡⅢ</sup> and x<sup>41a</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptDisallowedParents(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDisallowedParents(ast.KindHeading)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Disallowed parents: heading",
+			md:   "# E=mc^2^\n\nE=mc^2^",
+			html: "<h1>E=mc^2^</h1>\n<p>E=mc<sup>2</sup></p>",
+		},
+		{
+			desc: "Disallowed parents: emphasis in a heading",
+			md:   "## *x^2^*",
+			html: "<h2><em>x^2^</em></h2>",
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDisallowedParents(ast.KindBlockquote)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Disallowed parents: ancestor of the paragraph",
+			md:   "> - x^2^\n\ny^2^",
+			html: "<blockquote>\n<ul>\n<li>x^2^</li>\n</ul>\n</blockquote>\n<p>y<sup>2</sup></p>",
+		},
+	})
+}
+
+func TestSuperscriptBreakHint(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithBreakHint(4)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Break hint: long superscript",
+			md:   `e^(x+y+z)^ and x^2^`,
+			html: `<p>e<sup>(x+y+z)</sup><wbr> and x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Break hint: content at the threshold",
+			md:   `x^abcd^`,
+			html: `<p>x<sup>abcd</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+		),
+		goldmark.WithExtensions(
+			NewSuperscript(WithBreakHint(4)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Break hint: XHTML",
+			md:   `e^(x+y+z)^`,
+			html: `<p>e<sup>(x+y+z)</sup><wbr /></p>`,
+		},
+	})
+}
+
+func TestSuperscriptWrap(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithWrap("<!--sup-->", "<!--/sup-->"), WithSourceAttribute()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Wrap: prefix and suffix around the element",
+			md:   `x^2^ and y^n^`,
+			html: `<p>x<!--sup--><sup data-md="^2^">2</sup><!--/sup--> and y<!--sup--><sup data-md="^n^">n</sup><!--/sup--></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithWrap("[", ""), WithBreakHint(2)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Wrap: prefix only, before a break hint",
+			md:   `x^123^`,
+			html: `<p>x[<sup>123</sup><wbr></p>`,
+		},
+	})
+}
+
+func TestSuperscriptContextClass(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithContextClass(), WithInlineContent()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Context class: top level",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+		{
+			desc: "Context class: emphasis and strong emphasis",
+			md:   `*x^2^* and **y^3^**`,
+			html: `<p><em>x<sup class="in-em">2</sup></em> and <strong>y<sup class="in-strong">3</sup></strong></p>`,
+		},
+		{
+			desc: "Context class: nested emphasis, innermost first",
+			md:   `***x^2^***`,
+			html: `<p><em><strong>x<sup class="in-strong in-em">2</sup></strong></em></p>`,
+		},
+		{
+			desc: "Context class: emphasis inside the superscript",
+			md:   `x^*n*^`,
+			html: `<p>x<sup><em>n</em></sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptFirstOccurrence(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithFirstOccurrence(func(index int, content []byte) []ast.Attribute {
+				if index > 0 {
+					return nil
+				}
+				return []ast.Attribute{{Name: []byte("id"), Value: append([]byte("first-"), content...)}}
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "First occurrence: only the first superscript",
+			md:   "x^a^ and y^b^\n\nz^c^",
+			html: "<p>x<sup id=\"first-a\">a</sup> and y<sup>b</sup></p>\n<p>z<sup>c</sup></p>",
+		},
+		{
+			desc: "First occurrence: index restarts for each document",
+			md:   `x^d^ and y^e^`,
+			html: `<p>x<sup id="first-d">d</sup> and y<sup>e</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptDebugComments(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithDebugComments(), WithSymbolSubstitution(map[string]string{"TM": "™"})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Debug comments: around the element",
+			md:   `x^2^ and y`,
+			html: `<p>x<!-- sup:start --><sup>2</sup><!-- sup:end --> and y</p>`,
+		},
+		{
+			desc: "Debug comments: around a substituted symbol",
+			md:   `Brand^TM^`,
+			html: `<p>Brand<!-- sup:start -->™<!-- sup:end --></p>`,
+		},
+	})
+
+	runTestCases(t, goldmark.New(goldmark.WithExtensions(Superscript)), []TestCase{
+		{
+			desc: "Debug comments: absent by default",
+			md:   `x^2^`,
+			html: `<p>x<sup>2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptCloseAtParagraphEnd(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCloseAtParagraphEnd()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Close at paragraph end: content ends the paragraph",
+			md:   "the answer is x^2.\n\nand y^3",
+			html: "<p>the answer is x<sup>2.</sup></p>\n<p>and y<sup>3</sup></p>",
+		},
+		{
+			desc: "Close at paragraph end: interior space",
+			md:   `the answer is x^2 or so`,
+			html: `<p>the answer is x^2 or so</p>`,
+		},
+		{
+			desc: "Close at paragraph end: earlier line of the paragraph",
+			md:   "x^2\nand more",
+			html: "<p>x^2\nand more</p>",
+		},
+		{
+			desc: "Close at paragraph end: closed superscripts are unaffected",
+			md:   `x^2^ and y^3`,
+			html: `<p>x<sup>2</sup> and y<sup>3</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptWhitespaceFunc(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithWhitespaceFunc(func(r rune) bool {
+				return r == ' ' || r == '\t'
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Whitespace func: thin space allowed in content",
+			md:   "x^10 000^",
+			html: "<p>x<sup>10 000</sup></p>",
+		},
+		{
+			desc: "Whitespace func: ASCII space still rejects",
+			md:   `x^10 000^`,
+			html: `<p>x^10 000^</p>`,
+		},
+	})
+
+	runTestCases(t, goldmark.New(goldmark.WithExtensions(Superscript)), []TestCase{
+		{
+			desc: "Whitespace func: thin space rejects by default",
+			md:   "x^10 000^",
+			html: "<p>x^10 000^</p>",
+		},
+		{
+			desc: "Whitespace func: multi-byte letters are not whitespace",
+			md:   `x^à^`,
+			html: `<p>x<sup>à</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptPDFStyle(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithPDFStyle(), WithLang("en")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "PDF style: inline style preset",
+			md:   `x^2^`,
+			html: `<p>x<sup lang="en" style="vertical-align:super;font-size:smaller">2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptFlatten(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithFlatten(), WithSourceAttribute(), WithDebugComments()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Flatten: content text only",
+			md:   `E=mc^2^`,
+			html: `<p>E=mc2</p>`,
+		},
+		{
+			desc: "Flatten: content is still escaped",
+			md:   `a^<b>^ and x^&amp;^`,
+			html: `<p>a&lt;b&gt; and x&amp;</p>`,
+		},
+	})
+}
+
+func TestSuperscriptRejectInvalidUTF8(t *testing.T) {
+	runTestCases(t, goldmark.New(goldmark.WithExtensions(Superscript)), []TestCase{
+		{
+			desc: "Invalid UTF-8: passed through by default",
+			md:   "x^a\x80b^",
+			html: "<p>x<sup>a\x80b</sup></p>",
+		},
+	})
+
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRejectInvalidUTF8()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Invalid UTF-8: stray continuation byte",
+			md:   "x^a\x80b^ and y^2^",
+			html: "<p>x^a\x80b^ and y<sup>2</sup></p>",
+		},
+		{
+			desc: "Invalid UTF-8: encoded surrogate half",
+			md:   "x^\xed\xa0\x80^",
+			html: "<p>x^\xed\xa0\x80^</p>",
+		},
+		{
+			desc: "Invalid UTF-8: valid multi-byte content",
+			md:   `x^é^`,
+			html: `<p>x<sup>é</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptCitation(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCitation(func(content []byte) (string, bool) {
+				if !isNumeric(content) {
+					return "", false
+				}
+				return string(content), true
+			})),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Citation: numeric marker",
+			md:   `as shown^12^`,
+			html: `<p>as shown<sup><a href="#cite-12" rel="cite">12</a></sup></p>`,
+		},
+		{
+			desc: "Citation: no match",
+			md:   `x^n^`,
+			html: `<p>x<sup>n</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptAllowedScripts(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithAllowedScripts(unicode.Latin, unicode.Common)),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Allowed scripts: Latin and Common",
+			md:   `x^2^ and 1^er^ and y^(é+1)^`,
+			html: `<p>x<sup>2</sup> and 1<sup>er</sup> and y<sup>(é+1)</sup></p>`,
+		},
+		{
+			desc: "Allowed scripts: Cyrillic rejected",
+			md:   `x^д^ and y^2д^`,
+			html: `<p>x^д^ and y^2д^</p>`,
+		},
+	})
+}
+
+func TestSuperscriptRomanceOrdinals(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithRomanceOrdinals()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Romance ordinals: masculine",
+			md:   `el 1^o^ piso`,
+			html: `<p>el 1º piso</p>`,
+		},
+		{
+			desc: "Romance ordinals: feminine",
+			md:   `a 2^a^ vez`,
+			html: `<p>a 2ª vez</p>`,
+		},
+		{
+			desc: "Romance ordinals: other content",
+			md:   `1^os^ and 3^er^`,
+			html: `<p>1<sup>os</sup> and 3<sup>er</sup></p>`,
+		},
+		{
+			desc: "Romance ordinals: not after a digit",
+			md:   `x^a^`,
+			html: `<p>x<sup>a</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptRawSup(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewSuperscript(WithDelimiters('^', '＾')),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Raw sup: passed through untouched",
+			md:   `x<sup>3</sup> and y<SUP class="n">4</SUP>`,
+			html: `<p>x<sup>3</sup> and y<SUP class="n">4</SUP></p>`,
+		},
+		{
+			desc: "Raw sup: carets inside are left alone",
+			md:   `x<sup>^2^</sup> and y<sup>＾3＾</sup>`,
+			html: `<p>x<sup>^2^</sup> and y<sup>＾3＾</sup></p>`,
+		},
+		{
+			desc: "Raw sup: superscripts next to it",
+			md:   `x^2^<sup>3</sup> and <sup>3</sup>y^2^`,
+			html: `<p>x<sup>2</sup><sup>3</sup> and <sup>3</sup>y<sup>2</sup></p>`,
+		},
+		{
+			desc: "Raw sup: other raw elements do not count",
+			md:   `<span>x^2^</span> and <supper>y^3^</supper>`,
+			html: `<p><span>x<sup>2</sup></span> and <supper>y<sup>3</sup></supper></p>`,
+		},
+		{
+			desc: "Raw sup: HTML block",
+			md:   "<sup>\nx^2^\n</sup>",
+			html: "<sup>\nx^2^\n</sup>",
+		},
+		{
+			desc: "Raw sup: several elements between superscripts",
+			md:   `<sup>1</sup> x^2^ <sup>^3^</sup> y^4^ z＾5＾ <sup>＾6＾</sup>`,
+			html: `<p><sup>1</sup> x<sup>2</sup> <sup>^3^</sup> y<sup>4</sup> z<sup>5</sup> <sup>＾6＾</sup></p>`,
+		},
+		{
+			desc: "Raw sup: element moved into a link label",
+			md:   `[<sup>a^1^](u) b^2^`,
+			html: `<p><a href="u"><sup>a^1^</a> b<sup>2</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptCommaSplit(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCommaSplit(""), WithAutoID("")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Comma split: one element per part",
+			md:   `x^2,3,4^ and y^5^`,
+			html: `<p>x<sup id="sup-1">2</sup>,<sup>3</sup>,<sup>4</sup> and y<sup id="sup-2">5</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithCommaSplit("<span>,</span>"), WithSpanMode("exp")),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Comma split: configured separator",
+			md:   `x^a,b^`,
+			html: `<p>x<span class="exp">a</span><span>,</span><span class="exp">b</span></p>`,
+		},
+	})
+
+	runTestCases(t, goldmark.New(goldmark.WithExtensions(Superscript)), []TestCase{
+		{
+			desc: "Comma split: commas kept in one element by default",
+			md:   `x^2,3,4^`,
+			html: `<p>x<sup>2,3,4</sup></p>`,
+		},
+	})
+}
+
+func TestSuperscriptSymmetricOnly(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithSymmetricOnly()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Symmetric only: even caret count",
+			md:   `x^2^ and y^3^`,
+			html: `<p>x<sup>2</sup> and y<sup>3</sup></p>`,
+		},
+		{
+			desc: "Symmetric only: odd caret count",
+			md:   `x^2^ and y^3`,
+			html: `<p>x^2^ and y^3</p>`,
+		},
+		{
+			desc: "Symmetric only: escaped caret not counted",
+			md:   `x^2^ and y\^3`,
+			html: `<p>x<sup>2</sup> and y^3</p>`,
+		},
+		{
+			desc: "Symmetric only: caret in a code span across lines not counted",
+			md:   "x^2^ and `a\n^` b",
+			html: "<p>x<sup>2</sup> and <code>a ^</code> b</p>",
+		},
+	})
+}
@@ -0,0 +1,75 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// caretCountKey is the parser.Context key under which the caret parity of the block
+// being parsed is cached for WithSymmetricOnly.
+var caretCountKey = parser.NewContextKey()
+
+// caretParity caches whether block has an odd number of carets.
+type caretParity struct {
+	block ast.Node
+	odd   bool
+}
+
+// oddCarets reports whether the lines of block contain an odd number of carets that
+// are neither escaped nor inside a code span. Inline parsing handles one block at a
+// time, so only the result for the most recent block is kept in pc.
+func oddCarets(block ast.Node, source []byte, pc parser.Context) bool {
+	if cached, ok := pc.Get(caretCountKey).(*caretParity); ok && cached.block == block {
+		return cached.odd
+	}
+
+	// The lines are joined so that code spans spanning a line break are skipped too
+	var text []byte
+	lines := block.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		text = append(text, segment.Value(source)...)
+	}
+	count := 0
+	for j := 0; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '`':
+			j = skipCodeSpan(text, j)
+		case '^':
+			count++
+		}
+	}
+
+	odd := count%2 == 1
+	pc.Set(caretCountKey, &caretParity{block: block, odd: odd})
+	return odd
+}
+
+// skipCodeSpan returns the index of the last backtick of the code span opened by the
+// backtick run at line[i], or of the opening run itself if the span is not closed
+// in line.
+func skipCodeSpan(line []byte, i int) int {
+	n := backtickRun(line, i)
+	for j := i + n; j < len(line); j++ {
+		if line[j] != '`' {
+			continue
+		}
+		m := backtickRun(line, j)
+		if m == n {
+			return j + m - 1
+		}
+		j += m - 1
+	}
+	return i + n - 1
+}
+
+// backtickRun returns the length of the run of backticks starting at line[i].
+func backtickRun(line []byte, i int) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == '`' {
+		n++
+	}
+	return n
+}
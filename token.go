@@ -0,0 +1,33 @@
+package superscript
+
+import (
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// IsValidToken reports whether token, such as ^2^, would be parsed as a single
+// superscript with the extension's options when it directly follows the character
+// before. Use '\n' for a token at the start of a line. The token must start with the
+// opening caret and is checked on its own, so options that look further back in the
+// line, such as WithSkipInMath, only see before.
+func (s *superscript) IsValidToken(before rune, token []byte) bool {
+	if len(token) == 0 || token[0] != '^' {
+		return false
+	}
+	source := make([]byte, 0, utf8.UTFMax+len(token))
+	source = utf8.AppendRune(source, before)
+	source = append(source, token...)
+	offset := len(source) - len(token)
+
+	reader := text.NewReader(source)
+	reader.Advance(offset)
+	parent := ast.NewParagraph()
+	if NewSuperscriptParserWithConfig(&s.Config).Parse(parent, reader, parser.NewContext()) == nil {
+		return false
+	}
+	_, pos := reader.Position()
+	return pos.Start == len(source)
+}
@@ -0,0 +1,91 @@
+package superscript
+
+import (
+	"testing"
+)
+
+func TestIsValidToken(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		opts   []SuperscriptOption
+		before rune
+		token  string
+		want   bool
+	}{
+		{
+			desc:   "IsValidToken: valid token",
+			before: 'x',
+			token:  "^2^",
+			want:   true,
+		},
+		{
+			desc:   "IsValidToken: multi-byte content",
+			before: 'x',
+			token:  "^n+1°^",
+			want:   true,
+		},
+		{
+			desc:   "IsValidToken: empty token",
+			before: 'x',
+			token:  "^^",
+		},
+		{
+			desc:   "IsValidToken: whitespace in content",
+			before: 'x',
+			token:  "^a b^",
+		},
+		{
+			desc:   "IsValidToken: unclosed token",
+			before: 'x',
+			token:  "^2",
+		},
+		{
+			desc:   "IsValidToken: trailing text after the token",
+			before: 'x',
+			token:  "^2^y",
+		},
+		{
+			desc:   "IsValidToken: after whitespace",
+			before: ' ',
+			token:  "^2^",
+		},
+		{
+			desc:   "IsValidToken: at the start of a line",
+			before: '\n',
+			token:  "^2^",
+		},
+		{
+			desc:   "IsValidToken: no opening caret",
+			before: 'x',
+			token:  "2^",
+		},
+		{
+			desc:   "IsValidToken: option rejects the content",
+			opts:   []SuperscriptOption{WithRequireNonNumeric()},
+			before: 'x',
+			token:  "^2^",
+		},
+		{
+			desc:   "IsValidToken: option changes the delimiter",
+			opts:   []SuperscriptOption{WithCloseDelimiter('°')},
+			before: 'x',
+			token:  "^2°",
+			want:   true,
+		},
+		{
+			desc:   "IsValidToken: trimmed punctuation",
+			opts:   []SuperscriptOption{WithTrimTrailingPunctuation()},
+			before: 'x',
+			token:  "^2.^",
+			want:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := NewSuperscript(tc.opts...).IsValidToken(tc.before, []byte(tc.token)); got != tc.want {
+				t.Errorf("IsValidToken(%q, %q) = %v, want %v", tc.before, tc.token, got, tc.want)
+			}
+		})
+	}
+}
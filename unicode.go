@@ -0,0 +1,48 @@
+package superscript
+
+import (
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/util"
+)
+
+// unicodeSuperscripts maps the characters with a Unicode superscript form, other than
+// the digits in superscriptDigits, to that form.
+var unicodeSuperscripts = map[rune]rune{
+	'+':      '⁺',
+	'-':      '⁻',
+	'\u2212': '⁻', // minus sign
+	'=':      '⁼',
+	'(':      '⁽',
+	')':      '⁾',
+	'i':      'ⁱ',
+	'n':      'ⁿ',
+}
+
+// superscriptRune returns the Unicode superscript form of c, if it has one.
+func superscriptRune(c rune) (rune, bool) {
+	if c >= '0' && c <= '9' {
+		return superscriptDigits[c-'0'], true
+	}
+	r, ok := unicodeSuperscripts[c]
+	return r, ok
+}
+
+// unicodeContent converts content to Unicode superscript characters for
+// WithUnicodeOutput. It returns the converted content when every character has a
+// superscript form, and otherwise nil.
+func unicodeContent(content []byte) []byte {
+	content = resolveReferences(util.UnescapePunctuations(content))
+	if len(content) == 0 {
+		return nil
+	}
+	out := make([]byte, 0, len(content)*3)
+	for _, c := range string(content) {
+		r, ok := superscriptRune(c)
+		if !ok {
+			return nil
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
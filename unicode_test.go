@@ -0,0 +1,66 @@
+package superscript
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestSuperscriptUnicodeOutput(t *testing.T) {
+	mdTest := goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithUnicodeOutput()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Unicode output: fully mappable",
+			md:   `x^2^ and 10^-(n+1)^`,
+			html: `<p>x² and 10⁻⁽ⁿ⁺¹⁾</p>`,
+		},
+		{
+			desc: "Unicode output: partially mappable",
+			md:   `x^2a^`,
+			html: `<p>x<sup>2a</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithUnicodeOutput(), WithUnicodePartialMarker()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Unicode partial marker: fully mappable",
+			md:   `x^12^`,
+			html: `<p>x¹²</p>`,
+		},
+		{
+			desc: "Unicode partial marker: partially mappable",
+			md:   `x^2a^`,
+			html: `<p>x<sup data-unicode="partial">2a</sup></p>`,
+		},
+		{
+			desc: "Unicode partial marker: unmappable",
+			md:   `1^st^`,
+			html: `<p>1<sup data-unicode="partial">st</sup></p>`,
+		},
+	})
+
+	mdTest = goldmark.New(
+		goldmark.WithExtensions(
+			NewSuperscript(WithUnicodePartialMarker()),
+		),
+	)
+
+	runTestCases(t, mdTest, []TestCase{
+		{
+			desc: "Unicode partial marker: no effect without Unicode output",
+			md:   `x^2a^`,
+			html: `<p>x<sup>2a</sup></p>`,
+		},
+	})
+}
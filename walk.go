@@ -0,0 +1,40 @@
+package superscript
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// Walk calls fn for every superscript node under doc, in document order. source is the
+// document source that the nodes' segments refer to. Walking stops at the first error
+// returned by fn, which Walk then returns.
+func Walk(doc ast.Node, source []byte, fn func(n *Node) error) error {
+	return ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Kind() != KindSuperscript {
+			return ast.WalkContinue, nil
+		}
+		if sup, ok := n.(*Node); ok {
+			if err := fn(sup); err != nil {
+				return ast.WalkStop, err
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// SuperscriptVisitor receives the superscripts of a document from Visit, for rendering
+// them into something other than HTML, such as a component tree.
+type SuperscriptVisitor interface {
+	// VisitSuperscript is called with the text content of a superscript, with
+	// backslash escapes resolved, and the attributes set on its node. Returning an
+	// error stops the visit.
+	VisitSuperscript(content []byte, attrs []ast.Attribute) error
+}
+
+// Visit calls v for every superscript node under doc, in document order, like Walk.
+// source is the document source that the nodes' segments refer to.
+func Visit(doc ast.Node, source []byte, v SuperscriptVisitor) error {
+	return Walk(doc, source, func(n *Node) error {
+		return v.VisitSuperscript(util.UnescapePunctuations(nodeContent(n, source)), n.Attributes())
+	})
+}
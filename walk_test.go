@@ -0,0 +1,76 @@
+package superscript
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestWalk(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(NewSuperscript()))
+	source := []byte("a^2^ + b^n+1^ = c^2^\n\n> quoted x^y^ and *em^3^*\n")
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var got []string
+	err := Walk(doc, source, func(n *Node) error {
+		got = append(got, string(nodeContent(n, source)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2", "n+1", "2", "y", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk visited %q, want %q", got, want)
+	}
+
+	stop := errors.New("stop")
+	visited := 0
+	err = Walk(doc, source, func(n *Node) error {
+		visited++
+		return stop
+	})
+	if err != stop || visited != 1 {
+		t.Errorf("Walk() = %v after %d nodes, want %v after 1", err, visited, stop)
+	}
+}
+
+// recordingVisitor is a SuperscriptVisitor that records what it visits.
+type recordingVisitor struct {
+	contents []string
+	ids      []string
+}
+
+func (v *recordingVisitor) VisitSuperscript(content []byte, attrs []ast.Attribute) error {
+	v.contents = append(v.contents, string(content))
+	id := ""
+	for _, attr := range attrs {
+		if string(attr.Name) == "id" {
+			id = string(attr.Value.([]byte))
+		}
+	}
+	v.ids = append(v.ids, id)
+	return nil
+}
+
+func TestVisit(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(NewSuperscript()))
+	source := []byte("# Title^1^\n\na^2^ and b^n\\+1^\n")
+	doc := md.Parser().Parse(text.NewReader(source))
+	doc.FirstChild().NextSibling().FirstChild().NextSibling().(*Node).SetAttributeString("id", []byte("sq"))
+
+	v := &recordingVisitor{}
+	if err := Visit(doc, source, v); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "2", "n+1"}; !reflect.DeepEqual(v.contents, want) {
+		t.Errorf("Visit visited %q, want %q", v.contents, want)
+	}
+	if want := []string{"", "sq", ""}; !reflect.DeepEqual(v.ids, want) {
+		t.Errorf("Visit passed ids %q, want %q", v.ids, want)
+	}
+}